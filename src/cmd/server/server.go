@@ -2,18 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"os"
 	"time"
 
 	"go.uber.org/fx"
+	"t03/internal/config"
 	"t03/internal/di"
 )
 
 func main() {
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	startCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	app := fx.New(di.Module)
+	app := fx.New(fx.Supply(cfg), di.Module)
 
 	if err := app.Start(startCtx); err != nil {
 		log.Fatal(err)
@@ -4,3 +4,15 @@ type SignUpRequest struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
 }
+
+type TokenResponse struct {
+	PlayerId     string `json:"player_id,omitempty"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
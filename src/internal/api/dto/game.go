@@ -1,16 +1,123 @@
 package dto
 
+import "time"
+
 type GameRequest struct {
-	Board [][]string `json:"board"`
-	Mode  string     `json:"mode"`
+	Board      [][]string `json:"board"`
+	Mode       string     `json:"mode"`
+	Width      int        `json:"width,omitempty"`
+	Height     int        `json:"height,omitempty"`
+	WinLength  int        `json:"winLength,omitempty"`
+	Difficulty string     `json:"difficulty,omitempty"`
+	Engine     string     `json:"engine,omitempty"`
+	Symbol     string     `json:"symbol,omitempty"`
+
+	PerMoveSeconds   int `json:"perMoveSeconds,omitempty"`
+	ClockSeconds     int `json:"clockSeconds,omitempty"`
+	IncrementSeconds int `json:"incrementSeconds,omitempty"`
+}
+
+// Row и Col — указатели, чтобы отличить пропущенную координату от нулевой.
+type MoveRequest struct {
+	Row        *int `json:"row"`
+	Col        *int `json:"col"`
+	MoveNumber int  `json:"moveNumber,omitempty"`
 }
 
 type GameResponse struct {
 	GameId    string     `json:"id"`
 	Board     [][]string `json:"board"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	WinLength int        `json:"winLength"`
 	PlayerXId string     `json:"playerX"`
 	PlayerOId string     `json:"playerO"`
-	Status    string     `json:"message"`
+	Message   string     `json:"message"`
+
+	// Status — одно из waiting, in_progress, draw, win, resigned, timeout, abandoned.
+	Status        string `json:"status"`
+	Mode          string `json:"mode"`
+	Turn          string `json:"turn,omitempty"`
+	CurrentPlayer string `json:"currentPlayer,omitempty"`
+	YourSymbol    string `json:"yourSymbol,omitempty"`
+	YourTurn      bool   `json:"yourTurn"`
+	WinnerId      string `json:"winner,omitempty"`
+	// В ultimate-игре — координаты малых досок в сетке 3x3.
+	WinningLine [][2]int `json:"winningLine,omitempty"`
+	MoveCount   int      `json:"moveCount"`
+	// Игрок, предложивший ничью, пока соперник не ответил.
+	DrawOfferedBy string `json:"drawOfferedBy,omitempty"`
+
+	TimeControl  *TimeControl `json:"timeControl,omitempty"`
+	Clocks       *Clocks      `json:"clocks,omitempty"`
+	TurnDeadline *time.Time   `json:"turnDeadline,omitempty"`
+
+	Difficulty string `json:"difficulty,omitempty"`
+	Engine     string `json:"engine,omitempty"`
+
+	SubBoards      []string `json:"subBoards,omitempty"`
+	ActiveSubBoard *int     `json:"activeSubBoard,omitempty"`
+}
+
+type TimeControl struct {
+	PerMoveSeconds   int `json:"perMoveSeconds,omitempty"`
+	ClockSeconds     int `json:"clockSeconds,omitempty"`
+	IncrementSeconds int `json:"incrementSeconds,omitempty"`
+}
+
+// Оставшееся время игроков в миллисекундах на момент ответа.
+type Clocks struct {
+	X int64 `json:"x"`
+	O int64 `json:"o"`
+}
+
+type SocketRequest struct {
+	Type       string `json:"type"`
+	Row        *int   `json:"row,omitempty"`
+	Col        *int   `json:"col,omitempty"`
+	MoveNumber int    `json:"moveNumber,omitempty"`
+}
+
+type GameEvent struct {
+	Type    string         `json:"type"`
+	Game    *GameResponse  `json:"game,omitempty"`
+	Code    string         `json:"code,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+	At      time.Time      `json:"at"`
+}
+
+type MoveResponse struct {
+	Number   int       `json:"number"`
+	PlayerId string    `json:"playerId"`
+	Row      int       `json:"row"`
+	Col      int       `json:"col"`
+	Symbol   string    `json:"symbol"`
+	MadeAt   time.Time `json:"madeAt"`
+}
+
+type HistoryResponse struct {
+	GameId string         `json:"id"`
+	Moves  []MoveResponse `json:"moves"`
+}
+
+type GameSummary struct {
+	GameId        string    `json:"id"`
+	Mode          string    `json:"mode"`
+	Status        string    `json:"status"`
+	YourSymbol    string    `json:"yourSymbol,omitempty"`
+	OpponentId    string    `json:"opponentId,omitempty"`
+	OpponentLogin string    `json:"opponentLogin,omitempty"`
+	CurrentPlayer string    `json:"currentPlayer,omitempty"`
+	YourTurn      bool      `json:"yourTurn"`
+	WinnerId      string    `json:"winner,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type GamesListResponse struct {
+	Games      []GameSummary `json:"games"`
+	NextCursor string        `json:"nextCursor,omitempty"`
 }
 
 type Stats struct {
@@ -18,5 +125,61 @@ type Stats struct {
 	Wins       int     `json:"wins"`
 	Losses     int     `json:"losses"`
 	Draws      int     `json:"draws"`
+	Abandoned  int     `json:"abandoned"`
+	WinRatePct float64 `json:"winrate"`
+	Rating     float64 `json:"rating"`
+	RatingRD   float64 `json:"ratingDeviation"`
+	RatedGames int     `json:"ratedGames"`
+}
+
+type RatingChange struct {
+	GameId   string    `json:"gameId"`
+	Rating   float64   `json:"rating"`
+	RatingRD float64   `json:"ratingDeviation"`
+	Delta    float64   `json:"delta"`
+	At       time.Time `json:"at"`
+}
+
+type RatingHistoryResponse struct {
+	PlayerId string         `json:"playerId"`
+	History  []RatingChange `json:"history"`
+}
+
+type MatchmakingRequest struct {
+	GameRequest
+	RatingBand float64 `json:"ratingBand,omitempty"`
+}
+
+type MatchmakingStatus struct {
+	Status         string     `json:"status"`
+	Mode           string     `json:"mode,omitempty"`
+	Position       int        `json:"position,omitempty"`
+	QueueSize      int        `json:"queueSize"`
+	JoinedAt       *time.Time `json:"joinedAt,omitempty"`
+	Rating         float64    `json:"rating,omitempty"`
+	RatingBand     float64    `json:"ratingBand,omitempty"`
+	WaitingSeconds int        `json:"waitingSeconds,omitempty"`
+	GameId         string     `json:"gameId,omitempty"`
+}
+
+type LeaderboardEntry struct {
+	Rank       int     `json:"rank"`
+	PlayerId   string  `json:"playerId"`
+	Login      string  `json:"login"`
+	Games      int     `json:"games"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
 	WinRatePct float64 `json:"winrate"`
+	Rating     float64 `json:"rating"`
+	RatingRD   float64 `json:"ratingDeviation"`
+}
+
+type LeaderboardResponse struct {
+	Sort     string             `json:"sort"`
+	Mode     string             `json:"mode"`
+	Window   string             `json:"window"`
+	Since    *time.Time         `json:"since,omitempty"`
+	MinGames int                `json:"minGames"`
+	Entries  []LeaderboardEntry `json:"entries"`
 }
@@ -27,12 +27,25 @@ func (ua *UserAuthenticator) Protect(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 
-		userID, err := ua.AuthService.AuthenticateBasic(auth)
+		userID, err := ua.AuthService.Authenticate(auth)
 		if err != nil {
-			http.Error(w, "Authentication failed: "+err.Error(), http.StatusUnauthorized)
+			writeError(w, err)
 			return
 		}
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
 		next(w, r.WithContext(ctx))
 	}
 }
+
+// Браузер не умеет передавать заголовки при открытии WebSocket и EventSource,
+// поэтому токен можно передать в параметре access_token.
+func (ua *UserAuthenticator) ProtectStream(next http.HandlerFunc) http.HandlerFunc {
+	protected := ua.Protect(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Header.Get("Authorization") == "" && query.Has("access_token") {
+			r.Header.Set("Authorization", "Bearer "+query.Get("access_token"))
+		}
+		protected(w, r)
+	}
+}
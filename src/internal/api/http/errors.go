@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"t03/internal/api/dto"
+	"t03/internal/domain"
+)
+
+var errorKinds = []struct {
+	kind   error
+	code   string
+	status int
+}{
+	{domain.ErrNotFound, "not_found", http.StatusNotFound},
+	{domain.ErrInvalidInput, "invalid_input", http.StatusBadRequest},
+	{domain.ErrNotYourTurn, "not_your_turn", http.StatusConflict},
+	{domain.ErrIllegalMove, "illegal_move", http.StatusUnprocessableEntity},
+	{domain.ErrGameOver, "game_over", http.StatusConflict},
+	{domain.ErrConflict, "conflict", http.StatusConflict},
+	{domain.ErrUnauthorized, "unauthorized", http.StatusUnauthorized},
+	{domain.ErrForbidden, "forbidden", http.StatusForbidden},
+}
+
+func toErrorResponse(err error) (int, dto.ErrorResponse) {
+	for _, k := range errorKinds {
+		if !errors.Is(err, k.kind) {
+			continue
+		}
+		response := dto.ErrorResponse{Code: k.code, Message: err.Error()}
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			response.Details = domainErr.Details
+		}
+		return k.status, response
+	}
+	// Внутренние ошибки не отдаём клиенту, только пишем в лог.
+	log.Printf("internal error: %v", err)
+	return http.StatusInternalServerError, dto.ErrorResponse{Code: "internal", Message: "internal server error"}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, response := toErrorResponse(err)
+	writeErrorResponse(w, status, response)
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, response dto.ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func invalidInput(message string) error {
+	return domain.NewError(domain.ErrInvalidInput, message)
+}
+
+func errUnauthorized() error {
+	return domain.NewError(domain.ErrUnauthorized, "unauthorized")
+}
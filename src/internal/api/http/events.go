@@ -0,0 +1,110 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"t03/internal/api"
+	"t03/internal/domain"
+)
+
+const streamKeepAlive = 30 * time.Second
+
+func (h *GameHandler) HandleGameEvents(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	game, err := h.GameService.GetGame(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkGamePlayer(game, playerId); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	events, unsubscribe := h.Events.Subscribe(game.GameId)
+	defer unsubscribe()
+
+	streamEvents(w, r, playerId, events)
+}
+
+func (h *GameHandler) HandleLobbyEvents(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	events, unsubscribe := h.Events.SubscribeLobby()
+	defer unsubscribe()
+
+	streamEvents(w, r, playerId, events)
+}
+
+// HandlePlayerEvents — личная лента игрока: найденный соперник и подключение к его партиям.
+func (h *GameHandler) HandlePlayerEvents(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+	pid, err := uuid.Parse(playerId)
+	if err != nil {
+		writeError(w, invalidInput("invalid player id"))
+		return
+	}
+
+	events, unsubscribe := h.Events.SubscribePlayer(pid)
+	defer unsubscribe()
+
+	streamEvents(w, r, playerId, events)
+}
+
+func streamEvents(w http.ResponseWriter, r *http.Request, playerId string, events <-chan domain.GameEvent) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, errors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(api.ToGameEvent(event, playerId))
+			if err != nil {
+				return
+			}
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -3,8 +3,10 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/gorilla/websocket"
 	"t03/internal/api"
 	"t03/internal/api/dto"
 	"t03/internal/domain"
@@ -13,34 +15,46 @@ import (
 type GameHandler struct {
 	GameService domain.GameService
 	UserService domain.UserService
+	Events      domain.GameEvents
+	Matchmaker  domain.Matchmaker
+	upgrader    websocket.Upgrader
 }
 
-func NewGameHandler(gameService domain.GameService, userService domain.UserService) *GameHandler {
+func NewGameHandler(gameService domain.GameService, userService domain.UserService, events domain.GameEvents, matchmaker domain.Matchmaker) *GameHandler {
 	return &GameHandler{
 		GameService: gameService,
 		UserService: userService,
+		Events:      events,
+		Matchmaker:  matchmaker,
+		upgrader:    newUpgrader(nil),
 	}
 }
 
 func (h *GameHandler) HandleNewGame(w http.ResponseWriter, r *http.Request) {
 	playerId, ok := UserIDFromCtx(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized())
 		return
 	}
 
 	var req dto.GameRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, invalidInput("invalid request body"))
 		return
 	}
 	if req.Mode == "" {
 		req.Mode = "human"
 	}
 
-	id, err := h.GameService.NewGame(playerId, req.Mode)
+	options, err := api.ToGameOptions(req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
+		return
+	}
+
+	id, err := h.GameService.NewGame(playerId, req.Mode, options)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 	resp := map[string]string{"id": id}
@@ -55,23 +69,23 @@ func (h *GameHandler) HandleGame(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		h.HandleGameMove(w, r)
 	default:
-		http.Error(w, "Method not supported", http.StatusMethodNotAllowed)
+		writeErrorResponse(w, http.StatusMethodNotAllowed, dto.ErrorResponse{Code: "method_not_allowed", Message: "method not supported"})
 	}
 }
 
 func (h *GameHandler) HandleConnectToGame(w http.ResponseWriter, r *http.Request) {
 	playerId, ok := UserIDFromCtx(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized())
 		return
 	}
 	id := strings.TrimPrefix(r.URL.Path, "/game/")
 	game, err := h.GameService.ConnectToGame(id, playerId)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
-	response := api.ToGameResponse(game)
+	response := api.ToGameResponse(game, playerId)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -79,15 +93,21 @@ func (h *GameHandler) HandleConnectToGame(w http.ResponseWriter, r *http.Request
 func (h *GameHandler) HandleGamesList(w http.ResponseWriter, r *http.Request) {
 	playerId, ok := UserIDFromCtx(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized())
 		return
 	}
-	gamesList, err := h.GameService.GetAvailableGames(playerId)
+	params := r.URL.Query()
+	query, err := api.ToGamesQuery(params["filter"], params.Get("cursor"), params.Get("limit"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
-	response := api.ToGamesListResponse(gamesList)
+	page, err := h.GameService.ListGames(playerId, query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	response := api.ToGamesListResponse(page, playerId)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 
@@ -95,13 +115,13 @@ func (h *GameHandler) HandleGamesList(w http.ResponseWriter, r *http.Request) {
 func (h *GameHandler) HandlePlayerStats(w http.ResponseWriter, r *http.Request) {
 	_, ok := UserIDFromCtx(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized())
 		return
 	}
 	id := strings.TrimPrefix(r.URL.Path, "/stats/")
 	stats, err := h.GameService.GetPlayerStats(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 	resStats := api.ToStats(stats)
@@ -111,15 +131,58 @@ func (h *GameHandler) HandlePlayerStats(w http.ResponseWriter, r *http.Request)
 
 }
 
+func (h *GameHandler) HandleRatingHistory(w http.ResponseWriter, r *http.Request) {
+	_, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+	limit, err := api.ToLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	id := r.PathValue("id")
+	history, err := h.GameService.GetRatingHistory(id, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ToRatingHistoryResponse(id, history))
+}
+
+func (h *GameHandler) HandleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	_, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+	params := r.URL.Query()
+	sort, mode, window := params.Get("sort"), params.Get("mode"), params.Get("window")
+	query, err := api.ToLeaderboardQuery(sort, mode, window, params.Get("minGames"), params.Get("limit"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	board, err := h.GameService.GetLeaderboard(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ToLeaderboardResponse(board, sort, mode, window))
+}
+
 func (h *GameHandler) HandleSignUpRequest(w http.ResponseWriter, r *http.Request) {
 	var data dto.SignUpRequest
 	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, invalidInput("invalid request body"))
 		return
 	}
 	_, err := h.UserService.Register(data)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -128,23 +191,52 @@ func (h *GameHandler) HandleSignUpRequest(w http.ResponseWriter, r *http.Request
 func (h *GameHandler) HandleSignInRequest(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
 
-	playerId, err := h.UserService.AuthenticateBasic(auth)
+	playerId, tokens, err := h.UserService.SignIn(auth)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ToTokenResponse(playerId, tokens))
+}
+
+func (h *GameHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, invalidInput("invalid request body"))
+		return
+	}
+
+	tokens, err := h.UserService.RefreshTokens(req.RefreshToken)
 	if err != nil {
-		http.Error(w, "authorization error "+err.Error(), http.StatusUnauthorized)
+		writeError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"player_id": playerId,
-	})
+	json.NewEncoder(w).Encode(api.ToTokenResponse("", tokens))
+}
+
+func (h *GameHandler) HandleSignOut(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeError(w, invalidInput("invalid request body"))
+		return
+	}
+
+	if err := h.UserService.SignOut(req.RefreshToken); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *GameHandler) HandleGameMove(w http.ResponseWriter, r *http.Request) {
 
 	playerId, ok := UserIDFromCtx(r.Context())
 	if !ok {
-		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		writeError(w, errUnauthorized())
 		return
 	}
 
@@ -152,22 +244,140 @@ func (h *GameHandler) HandleGameMove(w http.ResponseWriter, r *http.Request) {
 
 	var gameReq dto.GameRequest
 	if err := json.NewDecoder(r.Body).Decode(&gameReq); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, invalidInput("invalid request body"))
 		return
 	}
 
 	playerMove, err := api.ToDomainGame(id, gameReq)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, err)
 		return
 	}
 	playerMove, err = h.GameService.PlayerVsAi(playerMove, playerId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := api.ToGameResponse(playerMove, playerId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+
+}
+
+func (h *GameHandler) HandleMakeMove(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
 
-	response := api.ToGameResponse(playerMove)
+	id := r.PathValue("id")
+
+	var moveReq dto.MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&moveReq); err != nil {
+		writeError(w, invalidInput("invalid request body"))
+		return
+	}
+
+	row, col, err := moveCoords(moveReq.Row, moveReq.Col)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	game, err := h.GameService.MakeMove(id, playerId, row, col, moveReq.MoveNumber)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := api.ToGameResponse(game, playerId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func moveCoords(row, col *int) (int, int, error) {
+	if row == nil || col == nil {
+		return 0, 0, invalidInput("row and col are required")
+	}
+	return *row, *col, nil
+}
+
+func (h *GameHandler) HandleGameHistory(w http.ResponseWriter, r *http.Request) {
+	_, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	id := r.PathValue("id")
+	moves, err := h.GameService.GetGameHistory(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := api.ToHistoryResponse(id, moves)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *GameHandler) HandleGameReplay(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	id := r.PathValue("id")
+	moveNumber, err := strconv.Atoi(r.PathValue("move"))
+	if err != nil {
+		writeError(w, invalidInput("invalid move number"))
+		return
+	}
+
+	game, err := h.GameService.ReplayGame(id, moveNumber)
 	if err != nil {
-		response.Status = err.Error()
+		writeError(w, err)
+		return
 	}
+
+	response := api.ToGameResponse(game, playerId)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
+}
+
+// Пока соперник не подключился, сдача отзывает партию: статус abandoned без победителя.
+func (h *GameHandler) HandleResign(w http.ResponseWriter, r *http.Request) {
+	h.handleGameAction(w, r, h.GameService.Resign)
+}
+
+func (h *GameHandler) HandleOfferDraw(w http.ResponseWriter, r *http.Request) {
+	h.handleGameAction(w, r, h.GameService.OfferDraw)
+}
 
+func (h *GameHandler) HandleAcceptDraw(w http.ResponseWriter, r *http.Request) {
+	h.handleGameAction(w, r, h.GameService.AcceptDraw)
+}
+
+func (h *GameHandler) HandleDeclineDraw(w http.ResponseWriter, r *http.Request) {
+	h.handleGameAction(w, r, h.GameService.DeclineDraw)
+}
+
+func (h *GameHandler) handleGameAction(w http.ResponseWriter, r *http.Request, action func(gameID, playerID string) (*domain.Game, error)) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	game, err := action(r.PathValue("id"), playerId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := api.ToGameResponse(game, playerId)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
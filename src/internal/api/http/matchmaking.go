@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"t03/internal/api"
+	"t03/internal/api/dto"
+)
+
+func (h *GameHandler) HandleMatchmakingJoin(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	var req dto.MatchmakingRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, invalidInput("invalid request body"))
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = "human"
+	}
+	options, err := api.ToGameOptions(req.GameRequest)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ticket, err := h.Matchmaker.Join(playerId, req.Mode, options, req.RatingBand)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ToMatchmakingStatus(ticket))
+}
+
+func (h *GameHandler) HandleMatchmakingLeave(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	if err := h.Matchmaker.Leave(playerId); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GameHandler) HandleMatchmakingStatus(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	ticket, err := h.Matchmaker.Status(playerId)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.ToMatchmakingStatus(ticket))
+}
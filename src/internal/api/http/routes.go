@@ -3,36 +3,73 @@ package http
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 
 	"go.uber.org/fx"
 	"t03/internal/domain"
 )
 
-func RegisterRoutes(lc fx.Lifecycle, gameHandler *GameHandler, authService domain.UserService) {
+type ServerConfig struct {
+	Addr        string
+	StaticDir   string
+	ServeStatic bool
+	AllowSignup bool
+	// Кроме собственного, с каких origin браузер может открыть WebSocket партии.
+	AllowedOrigins []string
+}
+
+func RegisterRoutes(lc fx.Lifecycle, cfg ServerConfig, gameHandler *GameHandler, authService domain.UserService) {
 	mux := http.NewServeMux()
 
 	authenticator := NewUserAuthenticator(authService)
+	gameHandler.upgrader = newUpgrader(cfg.AllowedOrigins)
 
-	mux.HandleFunc("/signup", gameHandler.HandleSignUpRequest)
+	if cfg.AllowSignup {
+		mux.HandleFunc("/signup", gameHandler.HandleSignUpRequest)
+	}
 	mux.HandleFunc("/signin", gameHandler.HandleSignInRequest)
+	mux.HandleFunc("POST /token/refresh", gameHandler.HandleRefreshToken)
+	mux.HandleFunc("POST /signout", gameHandler.HandleSignOut)
 
 	mux.HandleFunc("/new-game", authenticator.Protect(gameHandler.HandleNewGame))
 	mux.HandleFunc("/game/", authenticator.Protect(gameHandler.HandleGame))
-	mux.HandleFunc("/games", authenticator.Protect(gameHandler.HandleGamesList))
+	mux.HandleFunc("POST /game/{id}/moves", authenticator.Protect(gameHandler.HandleMakeMove))
+	mux.HandleFunc("POST /game/{id}/resign", authenticator.Protect(gameHandler.HandleResign))
+	mux.HandleFunc("POST /game/{id}/offer-draw", authenticator.Protect(gameHandler.HandleOfferDraw))
+	mux.HandleFunc("POST /game/{id}/accept-draw", authenticator.Protect(gameHandler.HandleAcceptDraw))
+	mux.HandleFunc("POST /game/{id}/decline-draw", authenticator.Protect(gameHandler.HandleDeclineDraw))
+	mux.HandleFunc("GET /game/{id}/history", authenticator.Protect(gameHandler.HandleGameHistory))
+	mux.HandleFunc("GET /game/{id}/replay/{move}", authenticator.Protect(gameHandler.HandleGameReplay))
+	mux.HandleFunc("GET /game/{id}/ws", authenticator.ProtectStream(gameHandler.HandleGameSocket))
+	mux.HandleFunc("GET /game/{id}/events", authenticator.ProtectStream(gameHandler.HandleGameEvents))
+	mux.HandleFunc("GET /games/events", authenticator.ProtectStream(gameHandler.HandleLobbyEvents))
+	mux.HandleFunc("GET /games", authenticator.Protect(gameHandler.HandleGamesList))
+	mux.HandleFunc("POST /matchmaking/join", authenticator.Protect(gameHandler.HandleMatchmakingJoin))
+	mux.HandleFunc("DELETE /matchmaking", authenticator.Protect(gameHandler.HandleMatchmakingLeave))
+	mux.HandleFunc("GET /matchmaking", authenticator.Protect(gameHandler.HandleMatchmakingStatus))
+	mux.HandleFunc("GET /matchmaking/events", authenticator.ProtectStream(gameHandler.HandlePlayerEvents))
 	mux.HandleFunc("/stats/", authenticator.Protect(gameHandler.HandlePlayerStats))
+	mux.HandleFunc("GET /stats/{id}/rating-history", authenticator.Protect(gameHandler.HandleRatingHistory))
+	mux.HandleFunc("GET /leaderboard", authenticator.Protect(gameHandler.HandleLeaderboard))
 
-	mux.Handle("/", http.FileServer(http.Dir("static")))
+	if cfg.ServeStatic {
+		mux.Handle("/", http.FileServer(http.Dir(cfg.StaticDir)))
+	}
 
 	server := &http.Server{
-		Addr:    ":8080",
+		Addr:    cfg.Addr,
 		Handler: mux,
 	}
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			log.Println("Starting HTTP Server on :8080")
-			go server.ListenAndServe()
+			listener, err := net.Listen("tcp", cfg.Addr)
+			if err != nil {
+				return err
+			}
+			log.Println("Starting HTTP Server on " + listener.Addr().String())
+			go server.Serve(listener)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
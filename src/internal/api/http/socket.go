@@ -0,0 +1,168 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"t03/internal/api"
+	"t03/internal/api/dto"
+	"t03/internal/domain"
+)
+
+const (
+	socketWriteWait = 10 * time.Second
+	socketPongWait  = 60 * time.Second
+	socketPingEvery = socketPongWait * 9 / 10
+	socketReadLimit = 4096
+)
+
+// newUpgrader принимает соединения со своего origin и из allowedOrigins. Токен
+// передаётся в строке запроса, поэтому чужие страницы подключаться не должны.
+// Запросы без Origin приходят не из браузера и разрешены.
+func newUpgrader(allowedOrigins []string) websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			u, err := url.Parse(origin)
+			if err != nil {
+				return false
+			}
+			return strings.EqualFold(u.Host, r.Host) || slices.Contains(allowedOrigins, origin)
+		},
+	}
+}
+
+// checkGamePlayer пускает в поток партии только её игроков.
+func checkGamePlayer(game *domain.Game, playerId string) error {
+	if playerId != game.Player_X.String() && playerId != game.Player_O.String() {
+		return domain.NewError(domain.ErrForbidden, "only players of this game can follow it")
+	}
+	return nil
+}
+
+func (h *GameHandler) HandleGameSocket(w http.ResponseWriter, r *http.Request) {
+	playerId, ok := UserIDFromCtx(r.Context())
+	if !ok {
+		writeError(w, errUnauthorized())
+		return
+	}
+
+	id := r.PathValue("id")
+	game, err := h.GameService.GetGame(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := checkGamePlayer(game, playerId); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	updates, unsubscribe := h.Events.Subscribe(game.GameId)
+	defer unsubscribe()
+
+	replies := make(chan dto.GameEvent, 1)
+	replies <- snapshotEvent(game, playerId)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer conn.Close()
+		writeSocket(conn, playerId, updates, replies, done)
+	}()
+	defer close(done)
+
+	conn.SetReadLimit(socketReadLimit)
+	conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(socketPongWait))
+	})
+
+	for {
+		var req dto.SocketRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		// Новое состояние партии придёт через подписку, отвечаем только на ошибку.
+		var err error
+		switch req.Type {
+		case "move":
+			var row, col int
+			if row, col, err = moveCoords(req.Row, req.Col); err == nil {
+				_, err = h.GameService.MakeMove(id, playerId, row, col, req.MoveNumber)
+			}
+		case "resign":
+			_, err = h.GameService.Resign(id, playerId)
+		case "offer-draw":
+			_, err = h.GameService.OfferDraw(id, playerId)
+		case "accept-draw":
+			_, err = h.GameService.AcceptDraw(id, playerId)
+		case "decline-draw":
+			_, err = h.GameService.DeclineDraw(id, playerId)
+		default:
+			err = invalidInput("unknown message type " + req.Type)
+		}
+		if err == nil {
+			continue
+		}
+		reply := errorEvent(err)
+
+		select {
+		case replies <- reply:
+		case <-stopped:
+			return
+		}
+	}
+}
+
+func writeSocket(conn *websocket.Conn, playerId string, updates <-chan domain.GameEvent, replies <-chan dto.GameEvent, done <-chan struct{}) {
+	ping := time.NewTicker(socketPingEvery)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case event, ok := <-updates:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			err = conn.WriteJSON(api.ToGameEvent(event, playerId))
+		case reply := <-replies:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			err = conn.WriteJSON(reply)
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait))
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func snapshotEvent(game *domain.Game, playerId string) dto.GameEvent {
+	response := api.ToGameResponse(game, playerId)
+	return dto.GameEvent{Type: "game", Game: &response, At: time.Now().UTC()}
+}
+
+func errorEvent(err error) dto.GameEvent {
+	_, response := toErrorResponse(err)
+	return dto.GameEvent{Type: "error", Code: response.Code, Message: response.Message, Details: response.Details, At: time.Now().UTC()}
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"cmp"
+	"encoding/base64"
 	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"t03/internal/api/dto"
 	"t03/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,7 +19,7 @@ func ToDomainGame(pasedId string, s dto.GameRequest) (*domain.Game, error) {
 
 	id, err := uuid.Parse(pasedId)
 	if err != nil {
-		return &game, err
+		return &game, domain.NewError(domain.ErrInvalidInput, "invalid game id").With("id", pasedId)
 	}
 	game.GameId = id
 
@@ -22,18 +28,21 @@ func ToDomainGame(pasedId string, s dto.GameRequest) (*domain.Game, error) {
 		game.Mode = domain.PVP
 	case "ai":
 		game.Mode = domain.PVE
+	case "ultimate":
+		game.Mode = domain.ULTIMATE
 	}
 
-	if len(s.Board) != 3 {
-		return &game, errors.New("board must have 3 rows")
+	if len(s.Board) < domain.MinBoardSize || len(s.Board) > domain.MaxBoardSize {
+		return &game, domain.NewError(domain.ErrInvalidInput, "board has an invalid number of rows")
 	}
 
-	for i := 0; i < 3; i++ {
-		if len(s.Board[i]) != 3 {
-			return &game, errors.New("each row must have 3 columns")
+	game.Board = domain.NewBoard(len(s.Board[0]), len(s.Board))
+	for i := range s.Board {
+		if len(s.Board[i]) != game.Board.Width() {
+			return &game, domain.NewError(domain.ErrInvalidInput, "all rows must have the same number of columns")
 		}
 
-		for j := 0; j < 3; j++ {
+		for j := range s.Board[i] {
 			switch s.Board[i][j] {
 			case "X":
 				game.Board[i][j] = domain.X
@@ -41,6 +50,8 @@ func ToDomainGame(pasedId string, s dto.GameRequest) (*domain.Game, error) {
 				game.Board[i][j] = domain.O
 			case "":
 				game.Board[i][j] = domain.Empty
+			default:
+				return &game, domain.NewError(domain.ErrInvalidInput, "invalid cell value").With("value", s.Board[i][j])
 			}
 		}
 	}
@@ -48,19 +59,83 @@ func ToDomainGame(pasedId string, s dto.GameRequest) (*domain.Game, error) {
 	return &game, nil
 }
 
-func ToGameResponse(game *domain.Game) dto.GameResponse {
-	board := make([][]string, 3)
+var difficulties = map[string]domain.Difficulty{
+	"random":  domain.DifficultyRandom,
+	"easy":    domain.DifficultyEasy,
+	"medium":  domain.DifficultyMedium,
+	"perfect": domain.DifficultyPerfect,
+}
+
+func ToGameOptions(s dto.GameRequest) (domain.GameOptions, error) {
+	options := domain.GameOptions{
+		Width:     s.Width,
+		Height:    s.Height,
+		WinLength: s.WinLength,
+		Engine:    s.Engine,
+		Symbol:    domain.X,
+		TimeControl: domain.TimeControl{
+			PerMove:   time.Duration(s.PerMoveSeconds) * time.Second,
+			Clock:     time.Duration(s.ClockSeconds) * time.Second,
+			Increment: time.Duration(s.IncrementSeconds) * time.Second,
+		},
+	}
+	switch s.Symbol {
+	case "", "X":
+	case "O":
+		options.Symbol = domain.O
+	case "random":
+		options.Symbol = domain.Empty
+	default:
+		return options, domain.NewError(domain.ErrInvalidInput, "symbol must be one of X, O, random")
+	}
+	if s.Difficulty != "" {
+		difficulty, ok := difficulties[s.Difficulty]
+		if !ok {
+			return options, domain.NewError(domain.ErrInvalidInput, "difficulty must be one of random, easy, medium, perfect")
+		}
+		options.Difficulty = difficulty
+	}
+	return options, nil
+}
+
+func toDifficultyName(difficulty domain.Difficulty) string {
+	for name, d := range difficulties {
+		if d == difficulty {
+			return name
+		}
+	}
+	return ""
+}
+
+var gameStatuses = map[domain.GameState]string{
+	domain.StatusWaiting:   "waiting",
+	domain.StatusTurn:      "in_progress",
+	domain.StatusDraw:      "draw",
+	domain.StatusWin:       "win",
+	domain.StatusResigned:  "resigned",
+	domain.StatusTimeout:   "timeout",
+	domain.StatusAbandoned: "abandoned",
+}
+
+var matchStatuses = map[domain.MatchStatus]string{
+	domain.MatchIdle:   "idle",
+	domain.MatchQueued: "queued",
+	domain.MatchFound:  "matched",
+}
+
+var gameModes = map[domain.Gametype]string{
+	domain.PVP:      "human",
+	domain.PVE:      "ai",
+	domain.ULTIMATE: "ultimate",
+}
+
+// viewerID — игрок, которому отправляется ответ: от него зависят yourSymbol и yourTurn.
+func ToGameResponse(game *domain.Game, viewerID string) dto.GameResponse {
+	board := make([][]string, game.Board.Height())
 	for i := range board {
-		board[i] = make([]string, 3)
-		for j := 0; j < 3; j++ {
-			switch game.Board[i][j] {
-			case domain.X:
-				board[i][j] = "X"
-			case domain.O:
-				board[i][j] = "O"
-			default:
-				board[i][j] = ""
-			}
+		board[i] = make([]string, game.Board.Width())
+		for j := range board[i] {
+			board[i][j] = toSymbol(game.Board[i][j])
 		}
 	}
 
@@ -74,28 +149,412 @@ func ToGameResponse(game *domain.Game) dto.GameResponse {
 		message = "Draw"
 	case domain.StatusWin:
 		message = "Player " + game.WinnerPID.String() + " won"
+	case domain.StatusResigned:
+		message = "Player " + game.WinnerPID.String() + " won by resignation"
+	case domain.StatusTimeout:
+		message = "Player " + game.WinnerPID.String() + " won on time"
+	case domain.StatusAbandoned:
+		message = "Game abandoned"
+		if forfeited(game.Mode, game.WinnerPID) {
+			message = "Player " + game.WinnerPID.String() + " won, opponent abandoned the game"
+		}
 	}
 
-	return dto.GameResponse{
+	response := dto.GameResponse{
 		GameId:    game.GameId.String(),
 		Board:     board,
+		Width:     game.Board.Width(),
+		Height:    game.Board.Height(),
+		WinLength: game.WinLength,
 		PlayerXId: game.Player_X.String(),
 		PlayerOId: game.Player_O.String(),
-		Status:    message,
+		Message:   message,
+		Status:    gameStatuses[game.State],
+		Mode:      gameModes[game.Mode],
+		MoveCount: game.Board.MovesCount(),
+	}
+
+	switch viewerID {
+	case game.Player_X.String():
+		response.YourSymbol = "X"
+	case game.Player_O.String():
+		response.YourSymbol = "O"
 	}
+	switch game.State {
+	case domain.StatusTurn:
+		response.CurrentPlayer = game.CurrentPID.String()
+		response.Turn = "O"
+		if game.CurrentPID == game.Player_X {
+			response.Turn = "X"
+		}
+		response.YourTurn = response.YourSymbol == response.Turn
+	case domain.StatusWin:
+		response.WinnerId = game.WinnerPID.String()
+		response.WinningLine = winningLine(game)
+	case domain.StatusResigned, domain.StatusTimeout:
+		response.WinnerId = game.WinnerPID.String()
+	case domain.StatusAbandoned:
+		if forfeited(game.Mode, game.WinnerPID) {
+			response.WinnerId = game.WinnerPID.String()
+		}
+	}
+	if game.DrawOfferedBy != uuid.Nil {
+		response.DrawOfferedBy = game.DrawOfferedBy.String()
+	}
+	if tc := game.TimeControl; tc != (domain.TimeControl{}) {
+		response.TimeControl = &dto.TimeControl{
+			PerMoveSeconds:   int(tc.PerMove / time.Second),
+			ClockSeconds:     int(tc.Clock / time.Second),
+			IncrementSeconds: int(tc.Increment / time.Second),
+		}
+	}
+	if game.TimeControl.Clock > 0 {
+		response.Clocks = toClocks(game)
+	}
+	if !game.TurnDeadline.IsZero() {
+		response.TurnDeadline = &game.TurnDeadline
+	}
+
+	if game.Mode == domain.PVE {
+		response.Difficulty = toDifficultyName(game.Difficulty)
+		response.Engine = game.Engine
+	}
+	if game.Ultimate != nil {
+		response.SubBoards = make([]string, 0, len(game.Ultimate.SubBoards))
+		for _, sub := range game.Ultimate.SubBoards {
+			switch sub.State {
+			case domain.StatusWin:
+				response.SubBoards = append(response.SubBoards, toSymbol(sub.Winner))
+			case domain.StatusDraw:
+				response.SubBoards = append(response.SubBoards, "draw")
+			default:
+				response.SubBoards = append(response.SubBoards, "")
+			}
+		}
+		active := game.Ultimate.ActiveSubBoard
+		response.ActiveSubBoard = &active
+	}
+	return response
 }
 
-func ToGamesListResponse(games *domain.GamesList) []string {
-	return games.Games.Strings()
+// Часы текущего игрока идут: вычитаем время, прошедшее с начала хода.
+func toClocks(game *domain.Game) *dto.Clocks {
+	x, o := game.ClockX, game.ClockO
+	if game.State == domain.StatusTurn && !game.TurnStartedAt.IsZero() {
+		elapsed := time.Since(game.TurnStartedAt)
+		if game.CurrentPID == game.Player_X {
+			x = max(x-elapsed, 0)
+		} else {
+			o = max(o-elapsed, 0)
+		}
+	}
+	return &dto.Clocks{X: x.Milliseconds(), O: o.Milliseconds()}
+}
 
+func winningLine(game *domain.Game) [][2]int {
+	if game.Ultimate == nil {
+		return domain.WinningLine(game.Board, game.WinLength)
+	}
+	meta := domain.NewBoard(domain.SubBoardSize, domain.SubBoardSize)
+	for i, sub := range game.Ultimate.SubBoards {
+		meta[i/domain.SubBoardSize][i%domain.SubBoardSize] = sub.Winner
+	}
+	return domain.WinningLine(meta, domain.SubBoardSize)
 }
+
+func ToGameEvent(event domain.GameEvent, viewerID string) dto.GameEvent {
+	game := ToGameResponse(event.Game, viewerID)
+	return dto.GameEvent{
+		Type: string(event.Type),
+		Game: &game,
+		At:   event.At,
+	}
+}
+
+func ToHistoryResponse(gameId string, moves []domain.Move) dto.HistoryResponse {
+	response := dto.HistoryResponse{
+		GameId: gameId,
+		Moves:  make([]dto.MoveResponse, 0, len(moves)),
+	}
+	for _, move := range moves {
+		response.Moves = append(response.Moves, dto.MoveResponse{
+			Number:   move.Number,
+			PlayerId: move.PlayerID.String(),
+			Row:      move.Row,
+			Col:      move.Col,
+			Symbol:   toSymbol(move.Symbol),
+			MadeAt:   move.MadeAt,
+		})
+	}
+	return response
+}
+
+func toSymbol(cell domain.Cell) string {
+	switch cell {
+	case domain.X:
+		return "X"
+	case domain.O:
+		return "O"
+	default:
+		return ""
+	}
+}
+
+func ToGamesQuery(filters []string, cursor string, limit string) (domain.GamesQuery, error) {
+	var query domain.GamesQuery
+	var err error
+	for _, list := range filters {
+		for _, name := range strings.Split(list, ",") {
+			switch name {
+			case "mine":
+				query.Filter.Mine = true
+			case "open":
+				query.Filter.Open = true
+			case "finished":
+				query.Filter.Finished = true
+			case "ai":
+				query.Filter.AI = true
+			default:
+				return query, domain.NewError(domain.ErrInvalidInput, "filter must be one of mine, open, finished, ai").With("filter", name)
+			}
+		}
+	}
+	query.Limit, err = ToLimit(limit)
+	if err != nil {
+		return query, err
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return query, domain.NewError(domain.ErrInvalidInput, "invalid cursor")
+		}
+		query.After = after
+	}
+	return query, nil
+}
+
+// Курсор непрозрачен для клиента: время создания и id последней партии страницы.
+func encodeCursor(cursor *domain.GamesCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "_" + cursor.GameID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*domain.GamesCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "_")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	after := &domain.GamesCursor{}
+	if after.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, err
+	}
+	if after.GameID, err = uuid.Parse(id); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func ToGamesListResponse(page *domain.GamesPage, viewerID string) dto.GamesListResponse {
+	response := dto.GamesListResponse{Games: make([]dto.GameSummary, 0, len(page.Games))}
+	for _, game := range page.Games {
+		summary := dto.GameSummary{
+			GameId:    game.GameID.String(),
+			Mode:      gameModes[game.Mode],
+			Status:    gameStatuses[game.State],
+			CreatedAt: game.CreatedAt,
+			UpdatedAt: game.UpdatedAt,
+		}
+
+		opponent, login := game.Player_X, game.LoginX
+		switch viewerID {
+		case game.Player_X.String():
+			summary.YourSymbol = "X"
+			opponent, login = game.Player_O, game.LoginO
+		case game.Player_O.String():
+			summary.YourSymbol = "O"
+		default:
+			if opponent == uuid.Nil {
+				opponent, login = game.Player_O, game.LoginO
+			}
+		}
+		if opponent != uuid.Nil {
+			summary.OpponentId = opponent.String()
+			summary.OpponentLogin = login
+		}
+
+		switch game.State {
+		case domain.StatusTurn:
+			summary.CurrentPlayer = game.CurrentPID.String()
+			summary.YourTurn = summary.CurrentPlayer == viewerID
+		case domain.StatusWin, domain.StatusResigned, domain.StatusTimeout:
+			summary.WinnerId = game.WinnerPID.String()
+		case domain.StatusAbandoned:
+			if forfeited(game.Mode, game.WinnerPID) {
+				summary.WinnerId = game.WinnerPID.String()
+			}
+		}
+		response.Games = append(response.Games, summary)
+	}
+	if page.Next != nil {
+		response.NextCursor = encodeCursor(page.Next)
+	}
+	return response
+}
+
+// forfeited отличает партию, брошенную игроком, от так и не начавшейся:
+// в партии с ИИ победитель uuid.Nil.
+func forfeited(mode domain.Gametype, winner uuid.UUID) bool {
+	return mode == domain.PVE || winner != uuid.Nil
+}
+
 func ToStats(stats *domain.Stats) *dto.Stats {
 	return &dto.Stats{
 		TotalGames: stats.TotalGames,
 		Wins:       stats.Wins,
 		Losses:     stats.Losses,
 		Draws:      stats.Draws,
+		Abandoned:  stats.Abandoned,
 		WinRatePct: stats.WinRatePct,
+		Rating:     roundRating(stats.Rating.Rating),
+		RatingRD:   roundRating(stats.Rating.RD),
+		RatedGames: stats.Rating.Games,
+	}
+
+}
+
+func ToTokenResponse(playerId string, tokens *domain.TokenPair) dto.TokenResponse {
+	return dto.TokenResponse{
+		PlayerId:     playerId,
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(time.Until(tokens.AccessExpiresAt).Seconds()),
+		RefreshToken: tokens.RefreshToken,
 	}
+}
+
+func ToMatchmakingStatus(ticket *domain.MatchTicket) dto.MatchmakingStatus {
+	response := dto.MatchmakingStatus{
+		Status:    matchStatuses[ticket.Status],
+		QueueSize: ticket.QueueSize,
+	}
+	switch ticket.Status {
+	case domain.MatchQueued:
+		response.Mode = ticket.Mode
+		response.Position = ticket.Position
+		response.JoinedAt = &ticket.JoinedAt
+		response.Rating = roundRating(ticket.Rating)
+		response.RatingBand = ticket.RatingBand
+		response.WaitingSeconds = int(time.Since(ticket.JoinedAt) / time.Second)
+	case domain.MatchFound:
+		response.GameId = ticket.GameID.String()
+	}
+	return response
+}
+
+// ToLimit разбирает необязательный параметр limit, 0 — значение по умолчанию.
+func ToLimit(limit string) (int, error) {
+	if limit == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return 0, domain.NewError(domain.ErrInvalidInput, "limit must be a positive number")
+	}
+	return n, nil
+}
+
+func roundRating(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func ToRatingHistoryResponse(playerID string, changes []domain.RatingChange) dto.RatingHistoryResponse {
+	response := dto.RatingHistoryResponse{
+		PlayerId: playerID,
+		History:  make([]dto.RatingChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		response.History = append(response.History, dto.RatingChange{
+			GameId:   change.GameID.String(),
+			Rating:   roundRating(change.Rating),
+			RatingRD: roundRating(change.RD),
+			Delta:    roundRating(change.Delta),
+			At:       change.At,
+		})
+	}
+	return response
+}
 
+var leaderboardSorts = map[string]domain.LeaderboardSort{
+	"rating":  domain.LeaderboardByRating,
+	"wins":    domain.LeaderboardByWins,
+	"winrate": domain.LeaderboardByWinRate,
+}
+
+var leaderboardModes = map[string][]domain.Gametype{
+	"all": nil,
+	"pvp": {domain.PVP, domain.ULTIMATE},
+	"pve": {domain.PVE},
+}
+
+var leaderboardWindows = map[string]domain.LeaderboardWindow{
+	"all":     domain.LeaderboardAllTime,
+	"monthly": domain.LeaderboardMonthly,
+	"weekly":  domain.LeaderboardWeekly,
+}
+
+// ToLeaderboardQuery разбирает параметры GET /leaderboard, пустые — значения по умолчанию.
+func ToLeaderboardQuery(sort, mode, window, minGames, limit string) (domain.LeaderboardQuery, error) {
+	var query domain.LeaderboardQuery
+	var ok bool
+	if query.Sort, ok = leaderboardSorts[cmp.Or(sort, "rating")]; !ok {
+		return query, domain.NewError(domain.ErrInvalidInput, "sort must be one of rating, wins, winrate")
+	}
+	if query.Modes, ok = leaderboardModes[cmp.Or(mode, "all")]; !ok {
+		return query, domain.NewError(domain.ErrInvalidInput, "mode must be one of all, pvp, pve")
+	}
+	if query.Window, ok = leaderboardWindows[cmp.Or(window, "all")]; !ok {
+		return query, domain.NewError(domain.ErrInvalidInput, "window must be one of all, monthly, weekly")
+	}
+	if minGames != "" {
+		n, err := strconv.Atoi(minGames)
+		if err != nil || n <= 0 {
+			return query, domain.NewError(domain.ErrInvalidInput, "minGames must be a positive number")
+		}
+		query.MinGames = n
+	}
+	var err error
+	query.Limit, err = ToLimit(limit)
+	return query, err
+}
+
+func ToLeaderboardResponse(board *domain.Leaderboard, sort, mode, window string) dto.LeaderboardResponse {
+	response := dto.LeaderboardResponse{
+		Sort:     cmp.Or(sort, "rating"),
+		Mode:     cmp.Or(mode, "all"),
+		Window:   cmp.Or(window, "all"),
+		MinGames: board.Query.MinGames,
+		Entries:  make([]dto.LeaderboardEntry, 0, len(board.Entries)),
+	}
+	if !board.Query.Since.IsZero() {
+		response.Since = &board.Query.Since
+	}
+	for _, e := range board.Entries {
+		response.Entries = append(response.Entries, dto.LeaderboardEntry{
+			Rank:       e.Rank,
+			PlayerId:   e.PlayerID.String(),
+			Login:      e.Login,
+			Games:      e.Games,
+			Wins:       e.Wins,
+			Losses:     e.Losses,
+			Draws:      e.Draws,
+			WinRatePct: e.WinRatePct,
+			Rating:     roundRating(e.Rating.Rating),
+			RatingRD:   roundRating(e.Rating.RD),
+		})
+	}
+	return response
 }
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"t03/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	for _, createdAt := range []time.Time{
+		time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 12, 0, 0, 123456789, time.UTC),
+		time.Date(2026, 1, 1, 15, 0, 0, 500000000, moscow),
+	} {
+		cursor := &domain.GamesCursor{CreatedAt: createdAt, GameID: uuid.New()}
+		encoded := encodeCursor(cursor)
+		decoded, err := decodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("decode %q: %v", encoded, err)
+		}
+		if !decoded.CreatedAt.Equal(createdAt) || decoded.GameID != cursor.GameID {
+			t.Errorf("got %+v, want %+v", decoded, cursor)
+		}
+	}
+}
+
+func TestToGamesQuery(t *testing.T) {
+	cursor := &domain.GamesCursor{CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), GameID: uuid.New()}
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name    string
+		filters []string
+		cursor  string
+		limit   string
+		want    domain.GamesQuery
+		wantErr bool
+	}{
+		{"defaults", nil, "", "", domain.GamesQuery{}, false},
+		{"filters", []string{"mine,open", "finished"}, "", "", domain.GamesQuery{Filter: domain.GamesFilter{Mine: true, Open: true, Finished: true}}, false},
+		{"ai filter", []string{"ai"}, "", "5", domain.GamesQuery{Filter: domain.GamesFilter{AI: true}, Limit: 5}, false},
+		{"cursor", nil, encodeCursor(cursor), "10", domain.GamesQuery{After: cursor, Limit: 10}, false},
+		{"unknown filter", []string{"mine,lost"}, "", "", domain.GamesQuery{}, true},
+		{"zero limit", nil, "", "0", domain.GamesQuery{}, true},
+		{"bad limit", nil, "", "ten", domain.GamesQuery{}, true},
+		{"cursor is not base64", nil, "!!!", "", domain.GamesQuery{}, true},
+		{"cursor without separator", nil, encode("2026-01-01T12:00:00Z"), "", domain.GamesQuery{}, true},
+		{"cursor with bad time", nil, encode("yesterday_" + cursor.GameID.String()), "", domain.GamesQuery{}, true},
+		{"cursor with bad id", nil, encode("2026-01-01T12:00:00Z_42"), "", domain.GamesQuery{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ToGamesQuery(tt.filters, tt.cursor, tt.limit)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidInput) {
+					t.Errorf("got %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(query, tt.want) {
+				t.Errorf("got %+v, want %+v", query, tt.want)
+			}
+		})
+	}
+}
+
+func TestGamesListNextCursor(t *testing.T) {
+	next := &domain.GamesCursor{CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), GameID: uuid.New()}
+	if response := ToGamesListResponse(&domain.GamesPage{}, ""); response.NextCursor != "" {
+		t.Errorf("last page has cursor %q", response.NextCursor)
+	}
+	response := ToGamesListResponse(&domain.GamesPage{Next: next}, "")
+	query, err := ToGamesQuery(nil, response.NextCursor, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !query.After.CreatedAt.Equal(next.CreatedAt) || query.After.GameID != next.GameID {
+		t.Errorf("cursor decoded to %+v, want %+v", query.After, next)
+	}
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"t03/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+func (svc *GameServiceImpl) AbandonGames() (int, error) {
+	now := time.Now().UTC()
+	waiting, err := svc.abandonStale(domain.StatusWaiting, svc.config.WaitingTTL, now)
+	if err != nil {
+		return waiting, err
+	}
+	inactive, err := svc.abandonStale(domain.StatusTurn, svc.config.InactivityTimeout, now)
+	return waiting + inactive, err
+}
+
+// abandonStale бросает партии в состоянии state, которые не менялись дольше ttl.
+func (svc *GameServiceImpl) abandonStale(state domain.GameState, ttl time.Duration, now time.Time) (int, error) {
+	if ttl <= 0 {
+		return 0, nil
+	}
+	cutoff := now.Add(-ttl)
+	ids, err := svc.repo.GetStaleGames(state, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	abandoned := 0
+	for _, id := range ids {
+		game, err := svc.repo.GetGame(id.String())
+		if err != nil {
+			return abandoned, err
+		}
+		if game.State != state || game.UpdatedAt.After(cutoff) {
+			continue
+		}
+		// Истёкшее время важнее бездействия: исход не зависит от того,
+		// какой из обходов доберётся до партии первым.
+		event := domain.EventTimeout
+		if !expireClock(game, now) {
+			abandonGame(game)
+			event = domain.EventAbandoned
+		}
+		err = svc.saveGame(game, event)
+		// Партию успели изменить между выборкой и сохранением.
+		if errors.Is(err, domain.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return abandoned, err
+		}
+		abandoned++
+	}
+	return abandoned, nil
+}
+
+// В идущей партии проигрывает тот, чей ход: он перестал ходить.
+func abandonGame(game *domain.Game) {
+	if game.State == domain.StatusTurn {
+		game.WinnerPID = opponentOf(game, game.CurrentPID.String())
+	}
+	game.State = domain.StatusAbandoned
+	game.DrawOfferedBy = uuid.Nil
+	game.TurnDeadline = time.Time{}
+}
+
+func RunAbandonSweeper(lc fx.Lifecycle, svc domain.GameService, config GameConfig) {
+	schedule(lc, config.AbandonInterval, func() {
+		if _, err := svc.AbandonGames(); err != nil {
+			log.Printf("failed to abandon stale games: %v", err)
+		}
+	})
+}
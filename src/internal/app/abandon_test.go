@@ -0,0 +1,168 @@
+package app
+
+import (
+	"t03/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testWaitingTTL = time.Hour
+	testInactivity = 24 * time.Hour
+)
+
+func newAbandonService() *GameServiceImpl {
+	svc := newTestService()
+	svc.config.WaitingTTL = testWaitingTTL
+	svc.config.InactivityTimeout = testInactivity
+	return svc
+}
+
+// staleGame сохраняет партию, которая не менялась idle.
+func staleGame(t *testing.T, svc *GameServiceImpl, game *domain.Game, idle time.Duration) string {
+	t.Helper()
+	game.GameId = uuid.New()
+	game.UpdatedAt = time.Now().UTC().Add(-idle)
+	game.CreatedAt = game.UpdatedAt
+	if err := svc.repo.SaveGame(game); err != nil {
+		t.Fatalf("save game: %v", err)
+	}
+	return game.GameId.String()
+}
+
+func waitingGame() *domain.Game {
+	game := clockGame(domain.TimeControl{}, uuid.Nil)
+	game.State, game.Player_O = domain.StatusWaiting, uuid.Nil
+	return game
+}
+
+func TestAbandonGames(t *testing.T) {
+	tests := []struct {
+		name    string
+		game    func() *domain.Game
+		idle    time.Duration
+		config  func(*GameConfig)
+		state   domain.GameState
+		winner  uuid.UUID
+		changed bool
+	}{
+		{"waiting past TTL", waitingGame, testWaitingTTL + time.Minute, nil, domain.StatusAbandoned, uuid.Nil, true},
+		{"waiting within TTL", waitingGame, testWaitingTTL - time.Minute, nil, domain.StatusWaiting, uuid.Nil, false},
+		{"waiting TTL disabled", waitingGame, 100 * testWaitingTTL, func(c *GameConfig) { c.WaitingTTL = 0 }, domain.StatusWaiting, uuid.Nil, false},
+		// Ход, которого так и не дождались, проигрывает тот, чья очередь.
+		{"inactive X forfeits", func() *domain.Game {
+			return clockGame(domain.TimeControl{}, playerX)
+		}, testInactivity + time.Minute, nil, domain.StatusAbandoned, playerO, true},
+		{"inactive O forfeits", func() *domain.Game {
+			return clockGame(domain.TimeControl{}, playerO)
+		}, testInactivity + time.Minute, nil, domain.StatusAbandoned, playerX, true},
+		{"inactive against AI", func() *domain.Game {
+			game := clockGame(domain.TimeControl{}, playerX)
+			game.Mode, game.Player_O = domain.PVE, uuid.Nil
+			return game
+		}, testInactivity + time.Minute, nil, domain.StatusAbandoned, uuid.Nil, true},
+		{"active game", func() *domain.Game {
+			return clockGame(domain.TimeControl{}, playerX)
+		}, testInactivity - time.Minute, nil, domain.StatusTurn, uuid.Nil, false},
+		{"inactivity timeout disabled", func() *domain.Game {
+			return clockGame(domain.TimeControl{}, playerX)
+		}, 100 * testInactivity, func(c *GameConfig) { c.InactivityTimeout = 0 }, domain.StatusTurn, uuid.Nil, false},
+		// Бездействие в ожидающей партии меряется WaitingTTL, а не InactivityTimeout.
+		{"waiting within inactivity timeout", waitingGame, testWaitingTTL + time.Minute, func(c *GameConfig) { c.WaitingTTL = testInactivity * 2 }, domain.StatusWaiting, uuid.Nil, false},
+		{"finished game", func() *domain.Game {
+			game := clockGame(domain.TimeControl{}, playerX)
+			game.State, game.WinnerPID = domain.StatusWin, playerX
+			return game
+		}, 100 * testInactivity, nil, domain.StatusWin, playerX, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newAbandonService()
+			if tt.config != nil {
+				tt.config(&svc.config)
+			}
+			game := tt.game()
+			game.DrawOfferedBy = game.Player_X
+			gameID := staleGame(t, svc, game, tt.idle)
+
+			count, err := svc.AbandonGames()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := map[bool]int{true: 1}[tt.changed]; count != want {
+				t.Errorf("abandoned %d games, want %d", count, want)
+			}
+			stored, err := svc.GetGame(gameID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.State != tt.state || stored.WinnerPID != tt.winner {
+				t.Errorf("state = %d, winner = %v, want %d won by %v", stored.State, stored.WinnerPID, tt.state, tt.winner)
+			}
+			if tt.changed && stored.DrawOfferedBy != uuid.Nil {
+				t.Errorf("draw offer %v not cleared", stored.DrawOfferedBy)
+			}
+		})
+	}
+}
+
+// Оба обхода смотрят на одну партию: исход не должен зависеть от их порядка,
+// а обход часов не должен продлевать жизнь брошенной партии.
+func TestAbandonGamesAndClockSweeper(t *testing.T) {
+	sweepers := map[string]func(svc *GameServiceImpl) error{
+		"abandon": func(svc *GameServiceImpl) error {
+			_, err := svc.AbandonGames()
+			return err
+		},
+		"clock": func(svc *GameServiceImpl) error {
+			_, err := svc.ExpireGames()
+			return err
+		},
+	}
+
+	tests := []struct {
+		name     string
+		deadline time.Duration // когда истекает ход относительно текущего момента
+		order    []string
+		state    domain.GameState
+	}{
+		{"expired clock, clock sweeper first", -time.Minute, []string{"clock", "abandon"}, domain.StatusTimeout},
+		{"expired clock, abandon sweeper first", -time.Minute, []string{"abandon", "clock"}, domain.StatusTimeout},
+		{"running clock, clock sweeper first", time.Minute, []string{"clock", "abandon"}, domain.StatusAbandoned},
+		{"running clock, abandon sweeper first", time.Minute, []string{"abandon", "clock"}, domain.StatusAbandoned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newAbandonService()
+			now := time.Now().UTC()
+			game := clockGame(domain.TimeControl{PerMove: testInactivity * 2}, playerO)
+			gameID := staleGame(t, svc, game, testInactivity+time.Hour)
+			stored, err := svc.GetGame(gameID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored.TurnStartedAt = now.Add(tt.deadline - stored.TimeControl.PerMove)
+			stored.TurnDeadline = now.Add(tt.deadline)
+			if err = svc.repo.SaveGame(stored); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range tt.order {
+				if err = sweepers[name](svc); err != nil {
+					t.Fatalf("%s sweeper: %v", name, err)
+				}
+			}
+			if stored, err = svc.GetGame(gameID); err != nil {
+				t.Fatal(err)
+			}
+			if stored.State != tt.state || stored.WinnerPID != playerX {
+				t.Errorf("state = %d, winner = %v, want %d won by %v", stored.State, stored.WinnerPID, tt.state, playerX)
+			}
+			if !stored.TurnDeadline.IsZero() {
+				t.Errorf("deadline %v not cleared", stored.TurnDeadline)
+			}
+		})
+	}
+}
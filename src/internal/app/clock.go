@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"log"
+	"t03/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+func validateTimeControl(tc domain.TimeControl) error {
+	if tc.PerMove < 0 || tc.Clock < 0 || tc.Increment < 0 {
+		return domain.NewError(domain.ErrInvalidInput, "time control values must not be negative")
+	}
+	if tc.Increment > 0 && tc.Clock == 0 {
+		return domain.NewError(domain.ErrInvalidInput, "increment requires a clock")
+	}
+	return nil
+}
+
+// startClock запускает отсчёт времени текущего игрока. ИИ ходит сразу,
+// поэтому его ход не ограничивается.
+func startClock(game *domain.Game, now time.Time) {
+	game.TurnStartedAt = now
+	game.TurnDeadline = time.Time{}
+	if game.State != domain.StatusTurn || game.Mode == domain.PVE && game.CurrentPID == uuid.Nil {
+		return
+	}
+
+	tc := game.TimeControl
+	if tc.PerMove > 0 {
+		game.TurnDeadline = now.Add(tc.PerMove)
+	}
+	if tc.Clock > 0 {
+		deadline := now.Add(*clockOf(game, game.CurrentPID))
+		if game.TurnDeadline.IsZero() || deadline.Before(game.TurnDeadline) {
+			game.TurnDeadline = deadline
+		}
+	}
+}
+
+// stopClock списывает время хода с часов сходившего игрока и добавляет инкремент.
+func stopClock(game *domain.Game, playerID uuid.UUID, now time.Time) {
+	if game.TimeControl.Clock == 0 || game.TurnStartedAt.IsZero() {
+		return
+	}
+	clock := clockOf(game, playerID)
+	*clock -= now.Sub(game.TurnStartedAt)
+	*clock += game.TimeControl.Increment
+}
+
+func clockOf(game *domain.Game, playerID uuid.UUID) *time.Duration {
+	if playerID == game.Player_X {
+		return &game.ClockX
+	}
+	return &game.ClockO
+}
+
+// expireClock завершает партию поражением текущего игрока, если его время вышло.
+func expireClock(game *domain.Game, now time.Time) bool {
+	if game.State != domain.StatusTurn || game.TurnDeadline.IsZero() || now.Before(game.TurnDeadline) {
+		return false
+	}
+	if game.TimeControl.Clock > 0 {
+		clock := clockOf(game, game.CurrentPID)
+		*clock = max(*clock-game.TurnDeadline.Sub(game.TurnStartedAt), 0)
+	}
+	game.State = domain.StatusTimeout
+	game.WinnerPID = opponentOf(game, game.CurrentPID.String())
+	game.DrawOfferedBy = uuid.Nil
+	game.TurnDeadline = time.Time{}
+	return true
+}
+
+// checkClock фиксирует проигрыш по времени, если его ещё не зафиксировал фоновый обход.
+func (svc *GameServiceImpl) checkClock(game *domain.Game, playerID string) error {
+	if !expireClock(game, time.Now().UTC()) {
+		return nil
+	}
+	if err := svc.saveGame(game, domain.EventTimeout); err != nil {
+		return err
+	}
+	return checkPlaying(game, playerID)
+}
+
+func (svc *GameServiceImpl) ExpireGames() (int, error) {
+	now := time.Now().UTC()
+	ids, err := svc.repo.GetExpiredGames(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		game, err := svc.repo.GetGame(id.String())
+		if err != nil {
+			return expired, err
+		}
+		if !expireClock(game, now) {
+			continue
+		}
+		err = svc.saveGame(game, domain.EventTimeout)
+		// Игрок успел сходить между выборкой и сохранением.
+		if errors.Is(err, domain.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// RunClockSweeper в фоне завершает партии, в которых игрок не уложился во время,
+// даже если к ним никто не обращается.
+func RunClockSweeper(lc fx.Lifecycle, svc domain.GameService, config GameConfig) {
+	schedule(lc, config.ClockSweepInterval, func() {
+		if _, err := svc.ExpireGames(); err != nil {
+			log.Printf("failed to expire games: %v", err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"t03/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	playerX = uuid.New()
+	playerO = uuid.New()
+	t0      = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func clockGame(tc domain.TimeControl, current uuid.UUID) *domain.Game {
+	return &domain.Game{
+		Mode:        domain.PVP,
+		Player_X:    playerX,
+		Player_O:    playerO,
+		State:       domain.StatusTurn,
+		CurrentPID:  current,
+		TimeControl: tc,
+		ClockX:      tc.Clock,
+		ClockO:      tc.Clock,
+	}
+}
+
+func TestStartClock(t *testing.T) {
+	tests := []struct {
+		name     string
+		game     *domain.Game
+		deadline time.Duration // 0 — ход не ограничен
+	}{
+		{"no time control", clockGame(domain.TimeControl{}, playerX), 0},
+		{"per move", clockGame(domain.TimeControl{PerMove: 10 * time.Second}, playerX), 10 * time.Second},
+		{"clock", clockGame(domain.TimeControl{Clock: time.Minute}, playerX), time.Minute},
+		{"per move shorter than clock", clockGame(domain.TimeControl{PerMove: 10 * time.Second, Clock: time.Minute}, playerX), 10 * time.Second},
+		{"clock shorter than per move", func() *domain.Game {
+			game := clockGame(domain.TimeControl{PerMove: 10 * time.Second, Clock: time.Minute}, playerO)
+			game.ClockO = 3 * time.Second
+			return game
+		}(), 3 * time.Second},
+		{"waiting for opponent", func() *domain.Game {
+			game := clockGame(domain.TimeControl{PerMove: 10 * time.Second}, playerX)
+			game.State = domain.StatusWaiting
+			return game
+		}(), 0},
+		{"ai turn", func() *domain.Game {
+			game := clockGame(domain.TimeControl{PerMove: 10 * time.Second}, uuid.Nil)
+			game.Mode, game.Player_O = domain.PVE, uuid.Nil
+			return game
+		}(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.game.TurnDeadline = t0.Add(-time.Hour)
+			startClock(tt.game, t0)
+			if !tt.game.TurnStartedAt.Equal(t0) {
+				t.Errorf("turn started at %v, want %v", tt.game.TurnStartedAt, t0)
+			}
+			var want time.Time
+			if tt.deadline > 0 {
+				want = t0.Add(tt.deadline)
+			}
+			if !tt.game.TurnDeadline.Equal(want) {
+				t.Errorf("deadline = %v, want %v", tt.game.TurnDeadline, want)
+			}
+		})
+	}
+}
+
+func TestStopClock(t *testing.T) {
+	tests := []struct {
+		name           string
+		tc             domain.TimeControl
+		player         uuid.UUID
+		started        time.Time
+		elapsed        time.Duration
+		clockX, clockO time.Duration
+	}{
+		{"clock", domain.TimeControl{Clock: time.Minute}, playerX, t0, 10 * time.Second, 50 * time.Second, time.Minute},
+		{"increment", domain.TimeControl{Clock: time.Minute, Increment: 2 * time.Second}, playerX, t0, 10 * time.Second, 52 * time.Second, time.Minute},
+		{"increment above spent time", domain.TimeControl{Clock: time.Minute, Increment: 5 * time.Second}, playerO, t0, time.Second, time.Minute, 64 * time.Second},
+		{"per move only", domain.TimeControl{PerMove: 10 * time.Second}, playerX, t0, 5 * time.Second, 0, 0},
+		{"turn not started", domain.TimeControl{Clock: time.Minute, Increment: 2 * time.Second}, playerX, time.Time{}, 10 * time.Second, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := clockGame(tt.tc, tt.player)
+			game.TurnStartedAt = tt.started
+			stopClock(game, tt.player, t0.Add(tt.elapsed))
+			if game.ClockX != tt.clockX || game.ClockO != tt.clockO {
+				t.Errorf("clocks = %v, %v, want %v, %v", game.ClockX, game.ClockO, tt.clockX, tt.clockO)
+			}
+		})
+	}
+}
+
+func TestExpireClock(t *testing.T) {
+	tests := []struct {
+		name           string
+		tc             domain.TimeControl
+		player         uuid.UUID
+		elapsed        time.Duration // сколько прошло с начала хода
+		expired        bool
+		clockX, clockO time.Duration
+	}{
+		{"before deadline", domain.TimeControl{PerMove: 10 * time.Second}, playerX, 9 * time.Second, false, 0, 0},
+		{"per move timeout", domain.TimeControl{PerMove: 10 * time.Second}, playerX, 10 * time.Second, true, 0, 0},
+		{"clock timeout", domain.TimeControl{Clock: 30 * time.Second}, playerO, 30 * time.Second, true, 30 * time.Second, 0},
+		// Часы списываются только до дедлайна, даже если обход пришёл позже.
+		{"clock timeout noticed late", domain.TimeControl{Clock: 30 * time.Second}, playerX, time.Hour, true, 0, 30 * time.Second},
+		{"per move timeout keeps clock", domain.TimeControl{PerMove: 10 * time.Second, Clock: time.Minute}, playerX, 15 * time.Second, true, 50 * time.Second, time.Minute},
+		{"clock still running", domain.TimeControl{PerMove: 10 * time.Second, Clock: time.Minute}, playerO, 5 * time.Second, false, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := clockGame(tt.tc, tt.player)
+			game.DrawOfferedBy = playerO
+			startClock(game, t0)
+
+			if got := expireClock(game, t0.Add(tt.elapsed)); got != tt.expired {
+				t.Fatalf("expired = %v, want %v", got, tt.expired)
+			}
+			if game.ClockX != tt.clockX || game.ClockO != tt.clockO {
+				t.Errorf("clocks = %v, %v, want %v, %v", game.ClockX, game.ClockO, tt.clockX, tt.clockO)
+			}
+			if !tt.expired {
+				if game.State != domain.StatusTurn || game.TurnDeadline.IsZero() {
+					t.Errorf("game changed before deadline: state %d, deadline %v", game.State, game.TurnDeadline)
+				}
+				return
+			}
+			winner := playerX
+			if tt.player == playerX {
+				winner = playerO
+			}
+			if game.State != domain.StatusTimeout || game.WinnerPID != winner {
+				t.Errorf("state = %d, winner = %v, want timeout won by %v", game.State, game.WinnerPID, winner)
+			}
+			if !game.TurnDeadline.IsZero() || game.DrawOfferedBy != uuid.Nil {
+				t.Errorf("deadline %v and draw offer %v not cleared", game.TurnDeadline, game.DrawOfferedBy)
+			}
+		})
+	}
+}
+
+func TestExpireClockIgnoresFinishedGames(t *testing.T) {
+	for _, state := range []domain.GameState{domain.StatusWaiting, domain.StatusDraw, domain.StatusWin, domain.StatusResigned, domain.StatusTimeout, domain.StatusAbandoned} {
+		game := clockGame(domain.TimeControl{PerMove: 10 * time.Second}, playerX)
+		game.TurnStartedAt, game.TurnDeadline = t0, t0.Add(10*time.Second)
+		game.State = state
+		if expireClock(game, t0.Add(time.Hour)) {
+			t.Errorf("state %d: game expired", state)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Партию, к которой ещё никто не подключился, создатель отзывает: она считается
+// брошенной без победителя и не попадает в статистику.
+func (svc *GameServiceImpl) Resign(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.State == domain.StatusWaiting && (playerID == game.Player_X.String() || playerID == game.Player_O.String()) {
+		abandonGame(game)
+		if err = svc.saveGame(game, domain.EventAbandoned); err != nil {
+			return nil, err
+		}
+		return game, nil
+	}
+	if err = svc.checkPlayingGame(game, playerID); err != nil {
+		return nil, err
+	}
+
+	game.State = domain.StatusResigned
+	game.WinnerPID = opponentOf(game, playerID)
+	game.DrawOfferedBy = uuid.Nil
+	if err = svc.saveGame(game, domain.EventResigned); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// Встречное предложение ничьей равносильно согласию.
+func (svc *GameServiceImpl) OfferDraw(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadPlayingGame(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Mode == domain.PVE {
+		return nil, domain.NewError(domain.ErrIllegalMove, "draw offers are not available against AI")
+	}
+
+	switch game.DrawOfferedBy {
+	case uuid.MustParse(playerID):
+		return nil, domain.NewError(domain.ErrIllegalMove, "draw is already offered")
+	case uuid.Nil:
+		game.DrawOfferedBy = uuid.MustParse(playerID)
+		err = svc.saveGame(game, domain.EventDrawOffered)
+	default:
+		err = svc.acceptDraw(game)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (svc *GameServiceImpl) AcceptDraw(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadDrawOffer(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if err = svc.acceptDraw(game); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (svc *GameServiceImpl) DeclineDraw(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadDrawOffer(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	game.DrawOfferedBy = uuid.Nil
+	if err = svc.saveGame(game, domain.EventDrawDeclined); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (svc *GameServiceImpl) acceptDraw(game *domain.Game) error {
+	game.State = domain.StatusDraw
+	game.DrawOfferedBy = uuid.Nil
+	return svc.saveGame(game, domain.EventDrawAccepted)
+}
+
+func (svc *GameServiceImpl) loadPlayingGame(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if err = svc.checkPlayingGame(game, playerID); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (svc *GameServiceImpl) checkPlayingGame(game *domain.Game, playerID string) error {
+	if err := svc.checkClock(game, playerID); err != nil {
+		return err
+	}
+	return checkPlaying(game, playerID)
+}
+
+// loadDrawOffer загружает партию, в которой соперник playerID предложил ничью.
+func (svc *GameServiceImpl) loadDrawOffer(gameID, playerID string) (*domain.Game, error) {
+	game, err := svc.loadPlayingGame(gameID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	if game.DrawOfferedBy == uuid.Nil || game.DrawOfferedBy.String() == playerID {
+		return nil, domain.NewError(domain.ErrIllegalMove, "there is no draw offer from the opponent")
+	}
+	return game, nil
+}
+
+// В партии с ИИ соперник — uuid.Nil.
+func opponentOf(game *domain.Game, playerID string) uuid.UUID {
+	if game.Player_X.String() == playerID {
+		return game.Player_O
+	}
+	return game.Player_X
+}
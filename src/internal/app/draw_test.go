@@ -0,0 +1,137 @@
+package app
+
+import (
+	"errors"
+	"t03/internal/domain"
+	"t03/internal/infra/events"
+	"t03/internal/infra/inmem"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestService() *GameServiceImpl {
+	return NewGameService(inmem.NewGameRepository(), events.NewBroker(), GameConfig{}, nil).(*GameServiceImpl)
+}
+
+// storeGame сохраняет партию между playerX и playerO, ход за current.
+func storeGame(t *testing.T, svc *GameServiceImpl, mode domain.Gametype, current uuid.UUID) string {
+	t.Helper()
+	game := clockGame(domain.TimeControl{}, current)
+	game.GameId, game.Mode = uuid.New(), mode
+	if err := svc.repo.SaveGame(game); err != nil {
+		t.Fatalf("save game: %v", err)
+	}
+	return game.GameId.String()
+}
+
+func TestResign(t *testing.T) {
+	tests := []struct {
+		name    string
+		player  uuid.UUID
+		waiting bool
+		state   domain.GameState
+		winner  uuid.UUID
+		wantErr error
+	}{
+		{"on own turn", playerX, false, domain.StatusResigned, playerO, nil},
+		{"on opponent's turn", playerO, false, domain.StatusResigned, playerX, nil},
+		{"stranger", uuid.New(), false, domain.StatusTurn, uuid.Nil, domain.ErrForbidden},
+		// Создатель отзывает партию, к которой никто не подключился.
+		{"creator of waiting game", playerX, true, domain.StatusAbandoned, uuid.Nil, nil},
+		{"stranger to waiting game", uuid.New(), true, domain.StatusWaiting, uuid.Nil, domain.ErrNotYourTurn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+			game := clockGame(domain.TimeControl{}, playerX)
+			game.GameId = uuid.New()
+			game.DrawOfferedBy = playerO
+			if tt.waiting {
+				game.State, game.Player_O, game.DrawOfferedBy = domain.StatusWaiting, uuid.Nil, uuid.Nil
+			}
+			if err := svc.repo.SaveGame(game); err != nil {
+				t.Fatalf("save game: %v", err)
+			}
+
+			_, err := svc.Resign(game.GameId.String(), tt.player.String())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			stored, err := svc.GetGame(game.GameId.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.State != tt.state || stored.WinnerPID != tt.winner {
+				t.Errorf("state = %d, winner = %v, want %d won by %v", stored.State, stored.WinnerPID, tt.state, tt.winner)
+			}
+			if tt.wantErr == nil && stored.DrawOfferedBy != uuid.Nil {
+				t.Errorf("draw offer %v not cleared", stored.DrawOfferedBy)
+			}
+		})
+	}
+}
+
+func TestDrawOffer(t *testing.T) {
+	type action func(svc *GameServiceImpl, gameID, playerID string) (*domain.Game, error)
+	offer := (*GameServiceImpl).OfferDraw
+	accept := (*GameServiceImpl).AcceptDraw
+	decline := (*GameServiceImpl).DeclineDraw
+
+	type step struct {
+		do      action
+		player  uuid.UUID
+		wantErr error
+	}
+	tests := []struct {
+		name    string
+		mode    domain.Gametype
+		steps   []step
+		state   domain.GameState
+		offered uuid.UUID
+	}{
+		{"offer", domain.PVP, []step{{offer, playerX, nil}}, domain.StatusTurn, playerX},
+		{"offer on opponent's turn", domain.PVP, []step{{offer, playerO, nil}}, domain.StatusTurn, playerO},
+		{"accept", domain.PVP, []step{{offer, playerX, nil}, {accept, playerO, nil}}, domain.StatusDraw, uuid.Nil},
+		{"decline", domain.PVP, []step{{offer, playerX, nil}, {decline, playerO, nil}}, domain.StatusTurn, uuid.Nil},
+		{"counter offer accepts", domain.PVP, []step{{offer, playerX, nil}, {offer, playerO, nil}}, domain.StatusDraw, uuid.Nil},
+		{"repeated offer", domain.PVP, []step{{offer, playerX, nil}, {offer, playerX, domain.ErrIllegalMove}}, domain.StatusTurn, playerX},
+		{"accept own offer", domain.PVP, []step{{offer, playerX, nil}, {accept, playerX, domain.ErrIllegalMove}}, domain.StatusTurn, playerX},
+		{"decline own offer", domain.PVP, []step{{offer, playerX, nil}, {decline, playerX, domain.ErrIllegalMove}}, domain.StatusTurn, playerX},
+		{"accept without offer", domain.PVP, []step{{accept, playerO, domain.ErrIllegalMove}}, domain.StatusTurn, uuid.Nil},
+		{"stranger", domain.PVP, []step{{offer, uuid.New(), domain.ErrForbidden}}, domain.StatusTurn, uuid.Nil},
+		{"against AI", domain.PVE, []step{{offer, playerX, domain.ErrIllegalMove}}, domain.StatusTurn, uuid.Nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+			gameID := storeGame(t, svc, tt.mode, playerX)
+			for i, s := range tt.steps {
+				if _, err := s.do(svc, gameID, s.player.String()); !errors.Is(err, s.wantErr) {
+					t.Fatalf("step %d: got %v, want %v", i, err, s.wantErr)
+				}
+			}
+			game, err := svc.GetGame(gameID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if game.State != tt.state || game.DrawOfferedBy != tt.offered {
+				t.Errorf("state = %d, offered by %v, want %d, %v", game.State, game.DrawOfferedBy, tt.state, tt.offered)
+			}
+			if game.WinnerPID != uuid.Nil {
+				t.Errorf("winner = %v, want none", game.WinnerPID)
+			}
+		})
+	}
+}
+
+func TestDrawOfferFinishedGame(t *testing.T) {
+	svc := newTestService()
+	gameID := storeGame(t, svc, domain.PVP, playerX)
+	if _, err := svc.Resign(gameID, playerO.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.OfferDraw(gameID, playerX.String()); !errors.Is(err, domain.ErrGameOver) {
+		t.Errorf("got %v, want ErrGameOver", err)
+	}
+}
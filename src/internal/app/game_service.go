@@ -1,26 +1,87 @@
 package app
 
 import (
-	"errors"
-	"math"
+	"math/rand/v2"
+	"strconv"
 	"t03/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var difficultyEngines = map[domain.Difficulty]string{
+	domain.DifficultyRandom:  "random",
+	domain.DifficultyEasy:    "heuristic",
+	domain.DifficultyMedium:  "minimax-shallow",
+	domain.DifficultyPerfect: "minimax",
+}
+
+const (
+	defaultGamesPageSize = 20
+	maxGamesPageSize     = 100
+)
+
+type GameConfig struct {
+	AIMoveBudget       time.Duration
+	ClockSweepInterval time.Duration
+	WaitingTTL         time.Duration
+	InactivityTimeout  time.Duration
+	AbandonInterval    time.Duration
+	RatingPeriod       time.Duration
+}
+
 type GameServiceImpl struct {
-	repo domain.GameRepository
+	repo    domain.GameRepository
+	events  domain.GameEvents
+	config  GameConfig
+	engines map[string]domain.AIEngine
 }
 
-func NewGameService(repo domain.GameRepository) domain.GameService {
-	return &GameServiceImpl{repo: repo}
+func NewGameService(repo domain.GameRepository, events domain.GameEvents, config GameConfig, engines []domain.AIEngine) domain.GameService {
+	svc := &GameServiceImpl{repo: repo, events: events, config: config, engines: make(map[string]domain.AIEngine, len(engines))}
+	for _, engine := range engines {
+		svc.engines[engine.Name()] = engine
+	}
+	return svc
 }
 
-func (svc *GameServiceImpl) NewGame(playerId string, gameMode string) (string, error) {
+func (svc *GameServiceImpl) NewGame(playerId string, gameMode string, options domain.GameOptions) (string, error) {
+	game, err := svc.newGame(playerId, gameMode, options)
+	if err != nil {
+		return "", err
+	}
+	startClock(game, game.CreatedAt)
+
+	event := domain.EventGameCreated
+	if game.State == domain.StatusWaiting {
+		event = domain.EventNewOpenGame
+	}
+	err = svc.saveGame(game, event)
+	if err != nil {
+		return "", err
+	}
+
+	if game.Mode == domain.PVE && game.Player_O != uuid.Nil {
+		if _, err = svc.aITurn(game, domain.X); err != nil {
+			return "", err
+		}
+	}
+	return game.GameId.String(), nil
+}
+
+// newGame собирает партию создателя, не сохраняя её.
+func (svc *GameServiceImpl) newGame(playerId string, gameMode string, options domain.GameOptions) (*domain.Game, error) {
 	gameID := uuid.New()
 	pid, err := uuid.Parse(playerId)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	options, err = normalizeOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := svc.engines[options.Engine]; options.Engine != "" && !ok {
+		return nil, domain.NewError(domain.ErrInvalidInput, "unknown AI engine "+options.Engine).With("engine", options.Engine)
 	}
 	var st domain.GameState
 	var mode domain.Gametype
@@ -31,37 +92,99 @@ func (svc *GameServiceImpl) NewGame(playerId string, gameMode string) (string, e
 	case "ai":
 		st = domain.StatusTurn
 		mode = domain.PVE
-
+	case "ultimate":
+		st = domain.StatusWaiting
+		mode = domain.ULTIMATE
+		options = domain.GameOptions{
+			Width:       domain.UltimateBoardSize,
+			Height:      domain.UltimateBoardSize,
+			WinLength:   domain.SubBoardSize,
+			Symbol:      options.Symbol,
+			TimeControl: options.TimeControl,
+		}
+	default:
+		return nil, domain.NewError(domain.ErrInvalidInput, "mode must be one of human, ai, ultimate")
 	}
 
 	game := &domain.Game{
-		GameId:     gameID,
-		Board:      domain.Board{},
-		Mode:       mode,
-		Player_X:   pid,
-		CurrentPID: pid,
-		State:      st,
+		GameId:      gameID,
+		Board:       domain.NewBoard(options.Width, options.Height),
+		WinLength:   options.WinLength,
+		Difficulty:  options.Difficulty,
+		Engine:      options.Engine,
+		Mode:        mode,
+		State:       st,
+		TimeControl: options.TimeControl,
+		ClockX:      options.TimeControl.Clock,
+		ClockO:      options.TimeControl.Clock,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if mode == domain.ULTIMATE {
+		game.Ultimate = domain.NewUltimateBoard()
+	}
+
+	symbol := options.Symbol
+	if symbol == domain.Empty {
+		symbol = []domain.Cell{domain.X, domain.O}[rand.IntN(2)]
+	}
+	if symbol == domain.X {
+		game.Player_X = pid
+	} else {
+		game.Player_O = pid
 	}
+	game.CurrentPID = game.Player_X
+	return game, nil
+}
 
-	err = svc.repo.SaveGame(game)
+// NewMatchedGame создаёт партию двух игроков, подобранных друг другу: оба
+// сразу в игре, стороны выбираются случайно, в лобби партия не попадает.
+func (svc *GameServiceImpl) NewMatchedGame(firstID, secondID string, gameMode string, options domain.GameOptions) (*domain.Game, error) {
+	if gameMode != "human" && gameMode != "ultimate" {
+		return nil, domain.NewError(domain.ErrInvalidInput, "mode must be one of human, ultimate")
+	}
+	second, err := uuid.Parse(secondID)
 	if err != nil {
-		return "", err
+		return nil, domain.NewError(domain.ErrInvalidInput, "invalid player id")
+	}
+	options.Symbol = domain.Empty
+	game, err := svc.newGame(firstID, gameMode, options)
+	if err != nil {
+		return nil, err
 	}
-	return gameID.String(), nil
+	if game.Player_X == uuid.Nil {
+		game.Player_X = second
+	} else {
+		game.Player_O = second
+	}
+	game.CurrentPID = game.Player_X
+	game.State = domain.StatusTurn
+	startClock(game, game.CreatedAt)
+
+	if err = svc.saveGame(game, domain.EventMatchFound); err != nil {
+		return nil, err
+	}
+	return game, nil
 }
 func (svc *GameServiceImpl) ConnectToGame(gameId, playerId string) (*domain.Game, error) {
 
-	game, err := svc.repo.GetGame(gameId)
+	game, err := svc.loadGame(gameId)
 	if err != nil {
 		return nil, err
 	}
-	if game.Player_X.String() != playerId && game.State == domain.StatusWaiting {
-		game.Player_O, err = uuid.Parse(playerId)
+	if game.Player_X.String() != playerId && game.Player_O.String() != playerId && game.State == domain.StatusWaiting {
+		pid, err := uuid.Parse(playerId)
 		if err != nil {
 			return nil, err
 		}
+		if game.Player_X == uuid.Nil {
+			game.Player_X = pid
+		} else {
+			game.Player_O = pid
+		}
+		game.CurrentPID = game.Player_X
 		game.State = domain.StatusTurn
-		err = svc.repo.SaveGame(game)
+		startClock(game, time.Now().UTC())
+		err = svc.saveGame(game, domain.EventPlayerJoined)
 		if err != nil {
 			return nil, err
 		}
@@ -69,15 +192,118 @@ func (svc *GameServiceImpl) ConnectToGame(gameId, playerId string) (*domain.Game
 	return game, nil
 }
 
-func (svc *GameServiceImpl) GetAvailableGames(id string) (*domain.GamesList, error) {
-	return svc.repo.GetAvailableGames(id)
+func (svc *GameServiceImpl) GetGame(gameID string) (*domain.Game, error) {
+	return svc.loadGame(gameID)
+}
+
+func (svc *GameServiceImpl) loadGame(gameID string) (*domain.Game, error) {
+	if _, err := uuid.Parse(gameID); err != nil {
+		return nil, domain.NewError(domain.ErrInvalidInput, "invalid game id").With("id", gameID)
+	}
+	return svc.repo.GetGame(gameID)
+}
+
+func parsePlayerID(playerID string) (uuid.UUID, error) {
+	pid, err := uuid.Parse(playerID)
+	if err != nil {
+		return uuid.Nil, domain.NewError(domain.ErrInvalidInput, "invalid player id")
+	}
+	return pid, nil
+}
+
+func (svc *GameServiceImpl) saveGame(game *domain.Game, event domain.EventType) error {
+	game.UpdatedAt = time.Now().UTC()
+	var err error
+	if rated(game) {
+		err = svc.repo.SaveRatedGame(game, svc.rateGame(game))
+	} else {
+		err = svc.repo.SaveGame(game)
+	}
+	if err != nil {
+		return err
+	}
+
+	svc.events.Publish(domain.GameEvent{Type: event, Game: game, At: game.UpdatedAt})
+	if event != domain.EventGameOver && game.State != domain.StatusTurn && game.State != domain.StatusWaiting {
+		svc.events.Publish(domain.GameEvent{Type: domain.EventGameOver, Game: game, At: game.UpdatedAt})
+	}
+	return nil
 }
+
+func (svc *GameServiceImpl) ListGames(playerID string, query domain.GamesQuery) (*domain.GamesPage, error) {
+	id, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	query.PlayerID = id
+	if query.Limit <= 0 {
+		query.Limit = defaultGamesPageSize
+	}
+	if query.Limit > maxGamesPageSize {
+		return nil, domain.NewError(domain.ErrInvalidInput, "limit must not exceed "+strconv.Itoa(maxGamesPageSize)).With("max", maxGamesPageSize)
+	}
+	return svc.repo.ListGames(query)
+}
+
 func (svc *GameServiceImpl) GetPlayerStats(playerID string) (*domain.Stats, error) {
-	id, err := uuid.Parse(playerID)
+	id, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := svc.repo.GetPlayerStats(id)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := svc.repo.GetRating(id)
 	if err != nil {
 		return nil, err
 	}
-	return svc.repo.GetPlayerStats(uuid.MustParse(id.String()))
+	stats.Rating = rating.Decayed(time.Now().UTC(), svc.config.RatingPeriod)
+	return stats, nil
+}
+
+func (svc *GameServiceImpl) GetGameHistory(gameID string) ([]domain.Move, error) {
+	game, err := svc.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	return game.Moves, nil
+}
+
+func (svc *GameServiceImpl) ReplayGame(gameID string, moveNumber int) (*domain.Game, error) {
+	game, err := svc.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if moveNumber < 0 || moveNumber > len(game.Moves) {
+		return nil, domain.NewError(domain.ErrInvalidInput, "move number is out of range, game has "+strconv.Itoa(len(game.Moves))+" moves").
+			With("moves", len(game.Moves))
+	}
+
+	if moveNumber == len(game.Moves) {
+		return game, nil
+	}
+
+	replay := *game
+	replay.Board = domain.NewBoard(game.Board.Width(), game.Board.Height())
+	replay.Moves = game.Moves[:moveNumber]
+	replay.WinnerPID = uuid.Nil
+	replay.TurnDeadline = time.Time{}
+	if game.Ultimate != nil {
+		replay.Ultimate = domain.NewUltimateBoard()
+	}
+	for _, move := range replay.Moves {
+		applyMove(&replay, move.Row, move.Col, move.Symbol)
+	}
+
+	if game.State != domain.StatusWaiting {
+		replay.State = domain.StatusTurn
+	}
+	replay.CurrentPID = game.Player_X
+	if moveNumber > 0 && replay.Moves[moveNumber-1].Symbol == domain.X {
+		replay.CurrentPID = game.Player_O
+	}
+	return &replay, nil
 }
 
 func (svc *GameServiceImpl) PlayerVsAi(playerMove *domain.Game, playerId string) (*domain.Game, error) {
@@ -88,7 +314,7 @@ func (svc *GameServiceImpl) PlayerVsAi(playerMove *domain.Game, playerId string)
 	}
 
 	if res.State == domain.StatusTurn && res.Mode == domain.PVE {
-		res, err = svc.aITurn(res, domain.O)
+		res, err = svc.aITurn(res, aiSymbol(res))
 		if err != nil {
 			return res, err
 		}
@@ -98,78 +324,191 @@ func (svc *GameServiceImpl) PlayerVsAi(playerMove *domain.Game, playerId string)
 }
 
 func (svc *GameServiceImpl) PlayerMove(game *domain.Game, playerId string) (*domain.Game, error) {
-	beforeMove, err := svc.repo.GetGame(game.GameId.String())
+	beforeMove, err := svc.loadGame(game.GameId.String())
 	if err != nil {
 		return beforeMove, err
 	}
+	if err = svc.checkClock(beforeMove, playerId); err != nil {
+		return beforeMove, err
+	}
 
-	var turn domain.Cell
-	switch beforeMove.State {
-	case domain.StatusWaiting:
-		return beforeMove, errors.New("waiting for another player to connect")
-	case domain.StatusTurn:
-		if playerId != beforeMove.Player_X.String() && playerId != beforeMove.Player_O.String() {
-			return beforeMove, errors.New("not your game")
-		}
-		if beforeMove.CurrentPID.String() != playerId {
-			return beforeMove, errors.New("wait for your turn")
-		}
-		if playerId == beforeMove.Player_X.String() {
-			turn = domain.X
-			beforeMove.CurrentPID = beforeMove.Player_O
-		} else if playerId == beforeMove.Player_O.String() {
-			turn = domain.O
-			beforeMove.CurrentPID = beforeMove.Player_X
-		}
-	case domain.StatusDraw:
-		return beforeMove, errors.New("played in a draw")
-	case domain.StatusWin:
-		return beforeMove, errors.New("player " + beforeMove.WinnerPID.String() + " win")
-
+	turn, err := startTurn(beforeMove, playerId)
+	if err != nil {
+		return beforeMove, err
 	}
 
-	err = validateBoard(&beforeMove.Board, &game.Board, turn)
+	row, col, err := validateBoard(beforeMove.Board, game.Board, turn)
 	if err != nil {
 		return beforeMove, err
 	}
+	if err = validateUltimateMove(beforeMove, row, col); err != nil {
+		return beforeMove, err
+	}
+
+	placeMove(beforeMove, uuid.MustParse(playerId), row, col, turn)
+	finishTurn(beforeMove, playerId)
+
+	if err = svc.saveGame(beforeMove, domain.EventMoveMade); err != nil {
+		return beforeMove, err
+	}
+
+	return beforeMove, nil
+}
+
+func (svc *GameServiceImpl) MakeMove(gameID, playerID string, row, col, moveNumber int) (*domain.Game, error) {
+	game, err := svc.loadGame(gameID)
+	if err != nil {
+		return game, err
+	}
+	if err = svc.checkClock(game, playerID); err != nil {
+		return game, err
+	}
+
+	turn, err := startTurn(game, playerID)
+	if err != nil {
+		return game, err
+	}
+
+	if moveNumber != 0 && moveNumber != game.Board.MovesCount()+1 {
+		return game, domain.NewError(domain.ErrConflict, "board is outdated, expected move "+strconv.Itoa(game.Board.MovesCount()+1)).
+			With("expectedMove", game.Board.MovesCount()+1)
+	}
+	if row < 0 || row >= len(game.Board) || col < 0 || col >= len(game.Board[row]) {
+		return game, domain.NewError(domain.ErrIllegalMove, "cell is out of the board").With("row", row).With("col", col)
+	}
+	if game.Board[row][col] != domain.Empty {
+		return game, domain.NewError(domain.ErrIllegalMove, "cell is already taken").With("row", row).With("col", col)
+	}
+	if err = validateUltimateMove(game, row, col); err != nil {
+		return game, err
+	}
+
+	placeMove(game, uuid.MustParse(playerID), row, col, turn)
+	finishTurn(game, playerID)
+
+	if err = svc.saveGame(game, domain.EventMoveMade); err != nil {
+		return game, err
+	}
+
+	if game.State == domain.StatusTurn && game.Mode == domain.PVE {
+		return svc.aITurn(game, aiSymbol(game))
+	}
 
-	beforeMove.Board = game.Board
+	return game, nil
+}
+
+func startTurn(game *domain.Game, playerId string) (domain.Cell, error) {
+	var turn domain.Cell
+	if err := checkPlaying(game, playerId); err != nil {
+		return turn, err
+	}
+	if game.CurrentPID.String() != playerId {
+		return turn, domain.NewError(domain.ErrNotYourTurn, "wait for your turn")
+	}
+	turn = domain.X
+	if playerId == game.Player_O.String() {
+		turn = domain.O
+	}
+	return turn, nil
+}
 
-	over, who := checkGameOver(beforeMove.Board)
+// checkPlaying проверяет, что партия идёт и playerId в ней участвует.
+func checkPlaying(game *domain.Game, playerId string) error {
+	switch game.State {
+	case domain.StatusWaiting:
+		return domain.NewError(domain.ErrNotYourTurn, "waiting for another player to connect")
+	case domain.StatusDraw:
+		return domain.NewError(domain.ErrGameOver, "played in a draw")
+	case domain.StatusWin:
+		return domain.NewError(domain.ErrGameOver, "player "+game.WinnerPID.String()+" win").With("winner", game.WinnerPID)
+	case domain.StatusResigned:
+		return domain.NewError(domain.ErrGameOver, "player resigned, "+game.WinnerPID.String()+" win").With("winner", game.WinnerPID)
+	case domain.StatusTimeout:
+		return domain.NewError(domain.ErrGameOver, "time is up, "+game.WinnerPID.String()+" win").With("winner", game.WinnerPID)
+	case domain.StatusAbandoned:
+		return domain.NewError(domain.ErrGameOver, "game was abandoned")
+	}
+	if playerId != game.Player_X.String() && playerId != game.Player_O.String() {
+		return domain.NewError(domain.ErrForbidden, "not your game")
+	}
+	return nil
+}
+
+func finishTurn(game *domain.Game, playerId string) {
+	game.CurrentPID = game.Player_X
+	if playerId == game.Player_X.String() {
+		game.CurrentPID = game.Player_O
+	}
+	over, who := isGameOver(game)
 	if over {
 		if who == domain.Empty {
-			beforeMove.State = domain.StatusDraw
+			game.State = domain.StatusDraw
 		} else {
-			beforeMove.State = domain.StatusWin
-			beforeMove.WinnerPID = uuid.MustParse(playerId)
+			game.State = domain.StatusWin
+			game.WinnerPID = uuid.MustParse(playerId)
 		}
 	}
+	now := time.Now().UTC()
+	stopClock(game, uuid.MustParse(playerId), now)
+	startClock(game, now)
+}
 
-	svc.repo.SaveGame(beforeMove)
+func isGameOver(game *domain.Game) (bool, domain.Cell) {
+	if game.Ultimate != nil {
+		return checkUltimateGameOver(game.Ultimate)
+	}
+	return domain.CheckGameOver(game.Board, game.WinLength)
+}
 
-	return beforeMove, nil
+// Ход снимает неотвеченное предложение ничьей.
+func placeMove(game *domain.Game, playerID uuid.UUID, row, col int, symbol domain.Cell) {
+	game.DrawOfferedBy = uuid.Nil
+	applyMove(game, row, col, symbol)
+	recordMove(game, playerID, row, col, symbol)
+}
+
+func applyMove(game *domain.Game, row, col int, symbol domain.Cell) {
+	game.Board[row][col] = symbol
+	updateUltimate(game, row, col)
+}
+
+func recordMove(game *domain.Game, playerID uuid.UUID, row, col int, symbol domain.Cell) {
+	game.Moves = append(game.Moves, domain.Move{
+		Number:   len(game.Moves) + 1,
+		PlayerID: playerID,
+		Row:      row,
+		Col:      col,
+		Symbol:   symbol,
+		MadeAt:   time.Now().UTC(),
+	})
 }
 
 func (svc *GameServiceImpl) aITurn(game *domain.Game, ai domain.Cell) (*domain.Game, error) {
-	beforeMove, err := svc.repo.GetGame(game.GameId.String())
+	beforeMove, err := svc.loadGame(game.GameId.String())
 	if err != nil {
 		return beforeMove, err
 	}
 
 	if beforeMove.State != domain.StatusTurn {
-		return beforeMove, errors.New("session finished")
+		return beforeMove, domain.NewError(domain.ErrGameOver, "session finished")
 	}
 
-	bestMove := aiMove(game.Board, ai)
-
-	if bestMove[0] == -1 {
-		return game, errors.New("no moves left")
+	engine, err := svc.engineFor(game)
+	if err != nil {
+		return game, err
+	}
+	bestMove, err := engine.BestMove(game.Board.Clone(), game.WinLength, ai, svc.config.AIMoveBudget)
+	if err != nil {
+		return game, err
 	}
 
-	game.Board[bestMove[0]][bestMove[1]] = ai
+	placeMove(game, uuid.Nil, bestMove.Row, bestMove.Col, ai)
 	game.CurrentPID = beforeMove.Player_X
+	if ai == domain.X {
+		game.CurrentPID = beforeMove.Player_O
+	}
 
-	over, who := checkGameOver(game.Board)
+	over, who := isGameOver(game)
 	if over {
 		if who == domain.Empty {
 			game.State = domain.StatusDraw
@@ -178,36 +517,72 @@ func (svc *GameServiceImpl) aITurn(game *domain.Game, ai domain.Cell) (*domain.G
 			game.WinnerPID = uuid.Nil
 		}
 	}
-	svc.repo.SaveGame(game)
+	startClock(game, time.Now().UTC())
+	if err = svc.saveGame(game, domain.EventMoveMade); err != nil {
+		return game, err
+	}
 	return game, nil
 
 }
 
-func aiMove(board domain.Board, ai domain.Cell) [2]int {
-	bestScore := math.MinInt
-	bestMove := [2]int{-1, -1}
-
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if board[i][j] == domain.Empty {
-				board[i][j] = ai
-				score := minimax(board, 0, false, ai)
-				board[i][j] = domain.Empty
-
-				if score > bestScore {
-					bestScore = score
-					bestMove = [2]int{i, j}
-				}
-			}
-		}
+// В игре с ИИ его место занимает uuid.Nil.
+func aiSymbol(game *domain.Game) domain.Cell {
+	if game.Player_X == uuid.Nil {
+		return domain.X
 	}
-	return bestMove
+	return domain.O
 }
 
-func validateBoard(oldBoard, newBoard *domain.Board, turn domain.Cell) error {
+func (svc *GameServiceImpl) engineFor(game *domain.Game) (domain.AIEngine, error) {
+	name := game.Engine
+	if name == "" {
+		name = difficultyEngines[game.Difficulty]
+	}
+	engine, ok := svc.engines[name]
+	if !ok {
+		return nil, domain.NewError(domain.ErrInvalidInput, "unknown AI engine "+name).With("engine", name)
+	}
+	return engine, nil
+}
+
+func normalizeOptions(options domain.GameOptions) (domain.GameOptions, error) {
+	if options.Width == 0 {
+		options.Width = domain.DefaultBoardSize
+	}
+	if options.Height == 0 {
+		options.Height = domain.DefaultBoardSize
+	}
+	if options.WinLength == 0 {
+		options.WinLength = min(options.Width, options.Height, domain.MaxWinLength)
+	}
+
+	if options.Width < domain.MinBoardSize || options.Width > domain.MaxBoardSize ||
+		options.Height < domain.MinBoardSize || options.Height > domain.MaxBoardSize {
+		return options, domain.NewError(domain.ErrInvalidInput, "board size must be between "+strconv.Itoa(domain.MinBoardSize)+" and "+strconv.Itoa(domain.MaxBoardSize)).
+			With("min", domain.MinBoardSize).With("max", domain.MaxBoardSize)
+	}
+	if options.WinLength < domain.MinBoardSize || options.WinLength > max(options.Width, options.Height) {
+		return options, domain.NewError(domain.ErrInvalidInput, "win length must be between "+strconv.Itoa(domain.MinBoardSize)+" and the longest board side").
+			With("min", domain.MinBoardSize).With("max", max(options.Width, options.Height))
+	}
+	if _, ok := difficultyEngines[options.Difficulty]; !ok {
+		return options, domain.NewError(domain.ErrInvalidInput, "unknown difficulty")
+	}
+	return options, validateTimeControl(options.TimeControl)
+}
+
+func validateBoard(oldBoard, newBoard domain.Board, turn domain.Cell) (int, int, error) {
 	moveCount := 0
+	row, col := -1, -1
+
+	if oldBoard.Height() != newBoard.Height() || oldBoard.Width() != newBoard.Width() {
+		return row, col, domain.NewError(domain.ErrIllegalMove, "board size does not match the game")
+	}
 
 	for i := range oldBoard {
+		if len(newBoard[i]) != len(oldBoard[i]) {
+			return row, col, domain.NewError(domain.ErrIllegalMove, "board size does not match the game")
+		}
 		for j := range oldBoard[i] {
 			oldCell := oldBoard[i][j]
 			newCell := newBoard[i][j]
@@ -218,92 +593,20 @@ func validateBoard(oldBoard, newBoard *domain.Board, turn domain.Cell) error {
 
 			case oldCell == domain.Empty && newCell == turn:
 				moveCount++
+				row, col = i, j
 
 			default:
-				return errors.New("board is corrupted")
+				return row, col, domain.NewError(domain.ErrIllegalMove, "board is corrupted").With("row", i).With("col", j)
 			}
 		}
 	}
 
 	if moveCount == 0 {
-		return errors.New("your turn")
+		return row, col, domain.NewError(domain.ErrIllegalMove, "your turn")
 	}
 	if moveCount > 1 {
-		return errors.New("only one move is allowed at a time")
+		return row, col, domain.NewError(domain.ErrIllegalMove, "only one move is allowed at a time")
 	}
 
-	return nil
-}
-
-func checkGameOver(board domain.Board) (bool, domain.Cell) {
-	for i := range 3 {
-		if board[i][1] != domain.Empty && (board[i][1] == board[i][0] && board[i][1] == board[i][2]) {
-			return true, board[i][1]
-		} else if board[1][i] != domain.Empty && (board[1][i] == board[0][i] && board[1][i] == board[2][i]) {
-			return true, board[1][i]
-		} else {
-			continue
-		}
-	}
-
-	if board[1][1] != domain.Empty && ((board[0][0] == board[1][1] && board[1][1] == board[2][2]) || (board[0][2] == board[1][1] && board[1][1] == board[2][0])) {
-		return true, board[1][1]
-	}
-
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			if board[i][j] == domain.Empty {
-				return false, domain.Empty
-			}
-
-		}
-
-	}
-
-	return true, domain.Empty
-}
-
-func minimax(board domain.Board, depth int, isMaximizing bool, ai domain.Cell) int {
-	isOver, winner := checkGameOver(board)
-	if isOver {
-		if winner == ai {
-			return 10 - depth // победа ИИ
-		} else if winner != domain.Empty {
-			return depth - 10 // победа игрока
-		}
-		return 0 // ничья
-	}
-
-	if isMaximizing {
-		best := math.MinInt
-		for i := 0; i < 3; i++ {
-			for j := 0; j < 3; j++ {
-				if board[i][j] == domain.Empty {
-					board[i][j] = ai
-					score := minimax(board, depth+1, false, ai)
-					board[i][j] = domain.Empty
-					best = max(best, score)
-				}
-			}
-		}
-		return best
-	} else {
-		best := math.MaxInt
-		player := domain.X
-		if ai == domain.X {
-			player = domain.O
-		}
-
-		for i := 0; i < 3; i++ {
-			for j := 0; j < 3; j++ {
-				if board[i][j] == domain.Empty {
-					board[i][j] = player
-					score := minimax(board, depth+1, true, ai)
-					board[i][j] = domain.Empty
-					best = min(best, score)
-				}
-			}
-		}
-		return best
-	}
+	return row, col, nil
 }
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strconv"
+	"t03/internal/domain"
+	"time"
+)
+
+const (
+	defaultLeaderboardSize = 50
+	maxLeaderboardSize     = 100
+	// Без порога первое место по доле побед займёт игрок с одной партией.
+	defaultWinRateMinGames = 5
+)
+
+func (svc *GameServiceImpl) GetLeaderboard(query domain.LeaderboardQuery) (*domain.Leaderboard, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultLeaderboardSize
+	}
+	if query.Limit > maxLeaderboardSize {
+		return nil, domain.NewError(domain.ErrInvalidInput, "limit must not exceed "+strconv.Itoa(maxLeaderboardSize)).With("max", maxLeaderboardSize)
+	}
+	if query.MinGames <= 0 {
+		query.MinGames = 1
+		if query.Sort == domain.LeaderboardByWinRate {
+			query.MinGames = defaultWinRateMinGames
+		}
+	}
+	now := time.Now().UTC()
+	query.Since = windowStart(query.Window, now)
+
+	entries, err := svc.repo.GetLeaderboard(query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+		// Как в статистике: отклонение растёт, пока игрок не играет. Сам рейтинг
+		// от этого не меняется, поэтому порядок выдачи остаётся прежним.
+		entries[i].Rating = entries[i].Rating.Decayed(now, svc.config.RatingPeriod)
+	}
+	return &domain.Leaderboard{Query: query, Entries: entries}, nil
+}
+
+func windowStart(window domain.LeaderboardWindow, now time.Time) time.Time {
+	year, month, day := now.Date()
+	switch window {
+	case domain.LeaderboardMonthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	case domain.LeaderboardWeekly:
+		sinceMonday := (int(now.Weekday()) + 6) % 7
+		return time.Date(year, month, day-sinceMonday, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"errors"
+	"t03/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestWindowStart(t *testing.T) {
+	// 2026-01-14 — среда.
+	wednesday := time.Date(2026, 1, 14, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		window domain.LeaderboardWindow
+		now    time.Time
+		want   time.Time
+	}{
+		{"all time", domain.LeaderboardAllTime, wednesday, time.Time{}},
+		{"monthly", domain.LeaderboardMonthly, wednesday, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"monthly on the first", domain.LeaderboardMonthly, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly", domain.LeaderboardWeekly, wednesday, time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"weekly on monday", domain.LeaderboardWeekly, time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"weekly on sunday", domain.LeaderboardWeekly, time.Date(2026, 1, 18, 23, 59, 0, 0, time.UTC), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"weekly across months", domain.LeaderboardWeekly, time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC)},
+		{"weekly across years", domain.LeaderboardWeekly, time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowStart(tt.window, tt.now); !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLeaderboardQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    domain.LeaderboardQuery
+		minGames int
+		limit    int
+		wantErr  error
+	}{
+		{"defaults", domain.LeaderboardQuery{}, 1, defaultLeaderboardSize, nil},
+		{"win rate needs more games", domain.LeaderboardQuery{Sort: domain.LeaderboardByWinRate}, defaultWinRateMinGames, defaultLeaderboardSize, nil},
+		{"explicit min games", domain.LeaderboardQuery{Sort: domain.LeaderboardByWinRate, MinGames: 2, Limit: 10}, 2, 10, nil},
+		{"limit too large", domain.LeaderboardQuery{Limit: maxLeaderboardSize + 1}, 0, 0, domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := newTestService().GetLeaderboard(tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if board.Query.MinGames != tt.minGames || board.Query.Limit != tt.limit {
+				t.Errorf("min games %d, limit %d, want %d, %d", board.Query.MinGames, board.Query.Limit, tt.minGames, tt.limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"t03/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Соперники подбираются только среди тех, кто хочет играть на тех же условиях.
+type matchCriteria struct {
+	mode        string
+	width       int
+	height      int
+	winLength   int
+	timeControl domain.TimeControl
+}
+
+type matchEntry struct {
+	playerID uuid.UUID
+	mode     string
+	options  domain.GameOptions
+	criteria matchCriteria
+	joinedAt time.Time
+	rating   float64
+	band     float64
+}
+
+// Найденную партию игрок из очереди узнаёт один раз; непрочитанные результаты
+// хранятся не дольше matchResultTTL.
+const matchResultTTL = 10 * time.Minute
+
+type matchResult struct {
+	gameID    uuid.UUID
+	matchedAt time.Time
+}
+
+// MatchmakerImpl держит очередь в памяти процесса: после перезапуска игрокам
+// нужно встать в очередь заново.
+type MatchmakerImpl struct {
+	mu      sync.Mutex
+	games   domain.GameService
+	queue   []*matchEntry             // в порядке постановки
+	pending map[uuid.UUID]bool        // игроки, для которых сейчас создаётся партия
+	matched map[uuid.UUID]matchResult // ждавший игрок → созданная для него партия
+}
+
+func NewMatchmaker(games domain.GameService) domain.Matchmaker {
+	return &MatchmakerImpl{games: games, pending: make(map[uuid.UUID]bool), matched: make(map[uuid.UUID]matchResult)}
+}
+
+// Join ставит игрока в очередь или сразу создаёт партию с самым давним
+// подходящим соперником. Повторный вызов меняет условия и ставит в конец очереди.
+func (mm *MatchmakerImpl) Join(playerID, mode string, options domain.GameOptions, ratingBand float64) (*domain.MatchTicket, error) {
+	pid, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	criteria, err := toMatchCriteria(mode, options)
+	if err != nil {
+		return nil, err
+	}
+	if ratingBand < 0 {
+		return nil, domain.NewError(domain.ErrInvalidInput, "rating band must not be negative")
+	}
+	rating, err := mm.games.GetRating(playerID)
+	if err != nil {
+		return nil, err
+	}
+	entry := &matchEntry{playerID: pid, mode: mode, options: options, criteria: criteria, joinedAt: time.Now().UTC(), rating: rating.Rating, band: ratingBand}
+
+	mm.mu.Lock()
+	if mm.pending[pid] {
+		mm.mu.Unlock()
+		return nil, errMatchPending()
+	}
+	// Status вызывают не все игроки, поэтому старые результаты чистятся и здесь.
+	mm.purgeMatched(entry.joinedAt)
+	mm.remove(pid)
+	opponent := mm.takeOpponent(entry)
+	if opponent == nil {
+		mm.queue = append(mm.queue, entry)
+		ticket := mm.ticket(pid)
+		mm.mu.Unlock()
+		return ticket, nil
+	}
+	// Оба игрока зарезервированы, пока партия создаётся вне блокировки.
+	mm.pending[pid], mm.pending[opponent.playerID] = true, true
+	mm.mu.Unlock()
+
+	// Партию создаёт тот, кто ждал дольше; оба игрока попадают в неё одним сохранением.
+	game, err := mm.games.NewMatchedGame(opponent.playerID.String(), playerID, opponent.mode, opponent.options)
+
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	delete(mm.pending, pid)
+	delete(mm.pending, opponent.playerID)
+	if err != nil {
+		// Соперник не виноват в ошибке и не должен терять место в очереди.
+		mm.queue = slices.Insert(mm.queue, 0, opponent)
+		return nil, err
+	}
+	mm.matched[opponent.playerID] = matchResult{gameID: game.GameId, matchedAt: time.Now().UTC()}
+	return &domain.MatchTicket{PlayerID: pid, Status: domain.MatchFound, QueueSize: len(mm.queue), GameID: game.GameId}, nil
+}
+
+func (mm *MatchmakerImpl) Leave(playerID string) error {
+	pid, err := parsePlayerID(playerID)
+	if err != nil {
+		return err
+	}
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	if mm.pending[pid] {
+		return errMatchPending()
+	}
+	mm.remove(pid)
+	return nil
+}
+
+func (mm *MatchmakerImpl) Status(playerID string) (*domain.MatchTicket, error) {
+	pid, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.purgeMatched(time.Now().UTC())
+	ticket := mm.ticket(pid)
+	delete(mm.matched, pid)
+	return ticket, nil
+}
+
+func (mm *MatchmakerImpl) takeOpponent(entry *matchEntry) *matchEntry {
+	for i, candidate := range mm.queue {
+		if candidate.criteria == entry.criteria && withinBand(candidate, entry) && withinBand(entry, candidate) {
+			mm.queue = slices.Delete(mm.queue, i, i+1)
+			return candidate
+		}
+	}
+	return nil
+}
+
+func (mm *MatchmakerImpl) remove(playerID uuid.UUID) {
+	delete(mm.matched, playerID)
+	mm.queue = slices.DeleteFunc(mm.queue, func(e *matchEntry) bool { return e.playerID == playerID })
+}
+
+func (mm *MatchmakerImpl) purgeMatched(now time.Time) {
+	for pid, result := range mm.matched {
+		if now.Sub(result.matchedAt) > matchResultTTL {
+			delete(mm.matched, pid)
+		}
+	}
+}
+
+func (mm *MatchmakerImpl) ticket(playerID uuid.UUID) *domain.MatchTicket {
+	ticket := &domain.MatchTicket{PlayerID: playerID, QueueSize: len(mm.queue)}
+	if result, ok := mm.matched[playerID]; ok {
+		ticket.Status = domain.MatchFound
+		ticket.GameID = result.gameID
+		return ticket
+	}
+	for i, e := range mm.queue {
+		if e.playerID == playerID {
+			ticket.Status = domain.MatchQueued
+			ticket.Mode = e.mode
+			ticket.Options = e.options
+			ticket.JoinedAt = e.joinedAt
+			ticket.Rating = e.rating
+			ticket.RatingBand = e.band
+			ticket.Position = i + 1
+			break
+		}
+	}
+	return ticket
+}
+
+func errMatchPending() error {
+	return domain.NewError(domain.ErrConflict, "match is being created, try again")
+}
+
+// withinBand проверяет, устраивает ли соперник игрока по рейтингу.
+func withinBand(player, opponent *matchEntry) bool {
+	return player.band == 0 || math.Abs(player.rating-opponent.rating) <= player.band
+}
+
+func toMatchCriteria(mode string, options domain.GameOptions) (matchCriteria, error) {
+	if mode != "human" && mode != "ultimate" {
+		return matchCriteria{}, domain.NewError(domain.ErrInvalidInput, "mode must be one of human, ultimate")
+	}
+	options, err := normalizeOptions(options)
+	if err != nil {
+		return matchCriteria{}, err
+	}
+	criteria := matchCriteria{mode: mode, timeControl: options.TimeControl}
+	// Размер доски ultimate фиксирован.
+	if mode == "human" {
+		criteria.width, criteria.height, criteria.winLength = options.Width, options.Height, options.WinLength
+	}
+	return criteria, nil
+}
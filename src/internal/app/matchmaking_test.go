@@ -0,0 +1,265 @@
+package app
+
+import (
+	"errors"
+	"t03/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeGames подменяет GameService: матчмейкеру нужны только рейтинг и создание партии.
+type fakeGames struct {
+	domain.GameService
+	ratings  map[string]float64
+	fail     error
+	onCreate func()
+	created  [][2]string // ждавший игрок, подключившийся игрок
+}
+
+func (f *fakeGames) GetRating(playerID string) (*domain.Rating, error) {
+	rating, ok := f.ratings[playerID]
+	if !ok {
+		rating = domain.DefaultRating
+	}
+	return &domain.Rating{Rating: rating, RD: domain.DefaultRD}, nil
+}
+
+func (f *fakeGames) NewMatchedGame(firstID, secondID string, gameMode string, options domain.GameOptions) (*domain.Game, error) {
+	if f.onCreate != nil {
+		f.onCreate()
+	}
+	if f.fail != nil {
+		return nil, f.fail
+	}
+	f.created = append(f.created, [2]string{firstID, secondID})
+	return &domain.Game{GameId: uuid.New()}, nil
+}
+
+func newTestMatchmaker() (*MatchmakerImpl, *fakeGames) {
+	games := &fakeGames{ratings: make(map[string]float64)}
+	return NewMatchmaker(games).(*MatchmakerImpl), games
+}
+
+func players(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return ids
+}
+
+func mustJoin(t *testing.T, mm *MatchmakerImpl, playerID, mode string, options domain.GameOptions, band float64) *domain.MatchTicket {
+	t.Helper()
+	ticket, err := mm.Join(playerID, mode, options, band)
+	if err != nil {
+		t.Fatalf("join %s: %v", playerID, err)
+	}
+	return ticket
+}
+
+func mustStatus(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket {
+	t.Helper()
+	ticket, err := mm.Status(playerID)
+	if err != nil {
+		t.Fatalf("status %s: %v", playerID, err)
+	}
+	return ticket
+}
+
+func TestMatchmakerPairsInJoinOrder(t *testing.T) {
+	mm, games := newTestMatchmaker()
+	p := players(3)
+
+	if ticket := mustJoin(t, mm, p[0], "human", domain.GameOptions{}, 0); ticket.Status != domain.MatchQueued || ticket.Position != 1 {
+		t.Fatalf("first player: %+v", ticket)
+	}
+	mustJoin(t, mm, p[1], "ultimate", domain.GameOptions{}, 0)
+	ticket := mustJoin(t, mm, p[2], "human", domain.GameOptions{}, 0)
+	if ticket.Status != domain.MatchFound || ticket.GameID == uuid.Nil {
+		t.Fatalf("third player: %+v", ticket)
+	}
+	if len(games.created) != 1 || games.created[0] != [2]string{p[0], p[2]} {
+		t.Fatalf("created games = %v, want [%s %s]", games.created, p[0], p[2])
+	}
+
+	// Результат подбора ждавший игрок получает один раз.
+	if found := mustStatus(t, mm, p[0]); found.Status != domain.MatchFound || found.GameID != ticket.GameID {
+		t.Errorf("waiting player status: %+v", found)
+	}
+	if again := mustStatus(t, mm, p[0]); again.Status != domain.MatchIdle {
+		t.Errorf("status after read: %+v", again)
+	}
+	if other := mustStatus(t, mm, p[1]); other.Status != domain.MatchQueued || other.Position != 1 || other.QueueSize != 1 {
+		t.Errorf("other player status: %+v", other)
+	}
+}
+
+func TestMatchmakerCriteriaMismatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		options        domain.GameOptions
+		otherMode      string
+		otherOptions   domain.GameOptions
+		wantMatchFound bool
+	}{
+		{"defaults", "human", domain.GameOptions{}, "human", domain.GameOptions{Width: 3, Height: 3, WinLength: 3}, true},
+		{"side preference ignored", "human", domain.GameOptions{Symbol: domain.X}, "human", domain.GameOptions{Symbol: domain.X}, true},
+		{"board size", "human", domain.GameOptions{}, "human", domain.GameOptions{Width: 5, Height: 5, WinLength: 4}, false},
+		{"mode", "human", domain.GameOptions{}, "ultimate", domain.GameOptions{}, false},
+		{"ultimate ignores board size", "ultimate", domain.GameOptions{}, "ultimate", domain.GameOptions{Width: 5, Height: 5}, true},
+		{"time control", "human", domain.GameOptions{TimeControl: domain.TimeControl{PerMove: 30 * time.Second}}, "human", domain.GameOptions{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mm, games := newTestMatchmaker()
+			p := players(2)
+			mustJoin(t, mm, p[0], tt.mode, tt.options, 0)
+			ticket := mustJoin(t, mm, p[1], tt.otherMode, tt.otherOptions, 0)
+			if got := ticket.Status == domain.MatchFound; got != tt.wantMatchFound {
+				t.Fatalf("matched = %v, want %v: %+v", got, tt.wantMatchFound, ticket)
+			}
+			if !tt.wantMatchFound && (ticket.Position != 2 || len(games.created) != 0) {
+				t.Errorf("unmatched player not queued: %+v, games %v", ticket, games.created)
+			}
+		})
+	}
+}
+
+func TestMatchmakerRatingBand(t *testing.T) {
+	tests := []struct {
+		name                      string
+		waitingRating, joinRating float64
+		waitingBand, joinBand     float64
+		wantMatchFound            bool
+	}{
+		{"no bands", 1500, 1800, 0, 0, true},
+		{"waiting player band too narrow", 1500, 1800, 100, 0, false},
+		{"joining player band too narrow", 1500, 1800, 0, 100, false},
+		{"both bands wide enough", 1500, 1800, 300, 400, true},
+		{"one band too narrow", 1500, 1800, 400, 299, false},
+		{"band is inclusive", 1800, 1500, 300, 300, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mm, games := newTestMatchmaker()
+			p := players(2)
+			games.ratings[p[0]], games.ratings[p[1]] = tt.waitingRating, tt.joinRating
+
+			if queued := mustJoin(t, mm, p[0], "human", domain.GameOptions{}, tt.waitingBand); queued.Rating != tt.waitingRating || queued.RatingBand != tt.waitingBand {
+				t.Fatalf("queued ticket: %+v", queued)
+			}
+			ticket := mustJoin(t, mm, p[1], "human", domain.GameOptions{}, tt.joinBand)
+			if got := ticket.Status == domain.MatchFound; got != tt.wantMatchFound {
+				t.Errorf("matched = %v, want %v: %+v", got, tt.wantMatchFound, ticket)
+			}
+		})
+	}
+}
+
+func TestMatchmakerRejectsNegativeBand(t *testing.T) {
+	mm, _ := newTestMatchmaker()
+	if _, err := mm.Join(uuid.NewString(), "human", domain.GameOptions{}, -1); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestMatchmakerRequeuesOpponentOnFailure(t *testing.T) {
+	mm, games := newTestMatchmaker()
+	p := players(4)
+	mustJoin(t, mm, p[0], "human", domain.GameOptions{}, 0)
+	mustJoin(t, mm, p[1], "human", domain.GameOptions{Width: 5, Height: 5}, 0)
+
+	games.fail = errors.New("storage is down")
+	if _, err := mm.Join(p[2], "human", domain.GameOptions{}, 0); !errors.Is(err, games.fail) {
+		t.Fatalf("join: got %v, want storage error", err)
+	}
+	if ticket := mustStatus(t, mm, p[0]); ticket.Status != domain.MatchQueued || ticket.Position != 1 {
+		t.Errorf("opponent lost its place: %+v", ticket)
+	}
+	if ticket := mustStatus(t, mm, p[2]); ticket.Status != domain.MatchIdle {
+		t.Errorf("failed player left in queue: %+v", ticket)
+	}
+	if len(mm.pending) != 0 {
+		t.Errorf("pending = %v, want empty", mm.pending)
+	}
+
+	games.fail = nil
+	ticket := mustJoin(t, mm, p[3], "human", domain.GameOptions{}, 0)
+	if ticket.Status != domain.MatchFound || games.created[0][0] != p[0] {
+		t.Errorf("requeued opponent not matched first: %+v, games %v", ticket, games.created)
+	}
+}
+
+func TestMatchmakerPendingPlayers(t *testing.T) {
+	mm, games := newTestMatchmaker()
+	p := players(2)
+	mustJoin(t, mm, p[0], "human", domain.GameOptions{}, 0)
+
+	// Пока партия создаётся, оба игрока не могут ни встать в очередь снова, ни выйти.
+	games.onCreate = func() {
+		for _, id := range p {
+			if _, err := mm.Join(id, "human", domain.GameOptions{}, 0); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("join %s while pending: got %v, want ErrConflict", id, err)
+			}
+			if err := mm.Leave(id); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("leave %s while pending: got %v, want ErrConflict", id, err)
+			}
+		}
+	}
+	if ticket := mustJoin(t, mm, p[1], "human", domain.GameOptions{}, 0); ticket.Status != domain.MatchFound {
+		t.Fatalf("join: %+v", ticket)
+	}
+	if len(games.created) != 1 || len(mm.queue) != 0 {
+		t.Errorf("games %v, queue %d, want one game and empty queue", games.created, len(mm.queue))
+	}
+}
+
+func TestMatchmakerMatchResultTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		age     time.Duration
+		expired bool
+		// expire удаляет просроченные результаты: через Status самого игрока
+		// или через Join любого другого.
+		expire func(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket
+	}{
+		{"fresh result on status", time.Minute, false, func(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket {
+			return mustStatus(t, mm, playerID)
+		}},
+		{"expired on status", matchResultTTL + time.Second, true, func(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket {
+			return mustStatus(t, mm, playerID)
+		}},
+		{"expired on someone else's join", matchResultTTL + time.Second, true, func(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket {
+			mustJoin(t, mm, uuid.NewString(), "human", domain.GameOptions{}, 0)
+			return nil
+		}},
+		{"fresh result kept on someone else's join", time.Minute, false, func(t *testing.T, mm *MatchmakerImpl, playerID string) *domain.MatchTicket {
+			mustJoin(t, mm, uuid.NewString(), "human", domain.GameOptions{}, 0)
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mm, _ := newTestMatchmaker()
+			p := players(2)
+			mustJoin(t, mm, p[0], "human", domain.GameOptions{}, 0)
+			mustJoin(t, mm, p[1], "human", domain.GameOptions{}, 0)
+
+			pid := uuid.MustParse(p[0])
+			result := mm.matched[pid]
+			result.matchedAt = result.matchedAt.Add(-tt.age)
+			mm.matched[pid] = result
+
+			ticket := tt.expire(t, mm, p[0])
+			if _, kept := mm.matched[pid]; kept == tt.expired && ticket == nil {
+				t.Errorf("result kept = %v, want %v", kept, !tt.expired)
+			}
+			if ticket != nil && (ticket.Status == domain.MatchFound) == tt.expired {
+				t.Errorf("status after %v: %+v", tt.age, ticket)
+			}
+		})
+	}
+}
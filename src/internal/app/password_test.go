@@ -0,0 +1,153 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"t03/internal/domain"
+	"t03/internal/infra/inmem"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct horse"
+
+func bcryptHash(t *testing.T, prefix string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Версии $2a$, $2b$ и $2y$ отличаются только префиксом.
+	return prefix + strings.TrimPrefix(string(hash), "$2a$")
+}
+
+// Хеш с параметрами argon2id, отличными от текущих.
+func outdatedArgonHash(password string) string {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte(password), salt, 2, 32*1024, 2, argonKeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argonPrefix, argon2.Version, 32*1024, 2, 2,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestVerifyPassword(t *testing.T) {
+	current, err := hashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		ok       bool
+		rehash   bool
+	}{
+		{"argon2id", current, testPassword, true, false},
+		{"argon2id wrong password", current, "wrong", false, false},
+		{"outdated argon2id", outdatedArgonHash(testPassword), testPassword, true, true},
+		{"outdated argon2id wrong password", outdatedArgonHash(testPassword), "wrong", false, false},
+		{"bcrypt $2a$", bcryptHash(t, "$2a$"), testPassword, true, true},
+		{"bcrypt $2b$", bcryptHash(t, "$2b$"), testPassword, true, true},
+		{"bcrypt $2y$", bcryptHash(t, "$2y$"), testPassword, true, true},
+		{"bcrypt wrong password", bcryptHash(t, "$2b$"), "wrong", false, false},
+		{"plaintext", testPassword, testPassword, true, true},
+		{"plaintext wrong password", testPassword, "wrong", false, false},
+		{"plaintext prefix", testPassword, "correct", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := verifyPassword(tt.stored, tt.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.ok || rehash != tt.rehash {
+				t.Errorf("got ok %v, rehash %v, want %v, %v", ok, rehash, tt.ok, tt.rehash)
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordRejectsCorruptHashes(t *testing.T) {
+	current, err := hashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(current, "$")
+
+	for name, stored := range map[string]string{
+		"argon2id missing part": strings.Join(parts[:5], "$"),
+		"argon2id version":      strings.Replace(current, "v=19", "v=16", 1),
+		"argon2id params":       strings.Replace(current, "m=65536", "m=x", 1),
+		"argon2id salt":         strings.Replace(current, parts[4], "!!!", 1),
+		"argon2id key":          strings.Replace(current, parts[5], "!!!", 1),
+		"bcrypt":                "$2b$10$short",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if ok, _, err := verifyPassword(stored, testPassword); ok || err == nil {
+				t.Errorf("got ok %v, err %v, want error", ok, err)
+			}
+		})
+	}
+}
+
+func TestLoginRehashesPassword(t *testing.T) {
+	current, err := hashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		rehashed bool
+	}{
+		{"plaintext", testPassword, testPassword, true},
+		{"bcrypt", bcryptHash(t, "$2y$"), testPassword, true},
+		{"outdated argon2id", outdatedArgonHash(testPassword), testPassword, true},
+		{"current argon2id", current, testPassword, false},
+		{"wrong password", testPassword, "wrong", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := inmem.NewGameRepository()
+			svc := NewUserService(repo, TokenConfig{})
+			user := &domain.User{ID: uuid.New(), Login: "alice", Password: tt.stored}
+			if err := repo.SaveUser(user); err != nil {
+				t.Fatal(err)
+			}
+
+			credentials := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:"+tt.password))
+			id, err := svc.AuthenticateBasic(credentials)
+			if tt.password != testPassword {
+				if !errors.Is(err, domain.ErrUnauthorized) {
+					t.Fatalf("got %v, want ErrUnauthorized", err)
+				}
+			} else if err != nil || id != user.ID.String() {
+				t.Fatalf("got %q, %v, want %s", id, err, user.ID)
+			}
+
+			stored, err := repo.GetUser("alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rehashed := stored.Password != tt.stored; rehashed != tt.rehashed {
+				t.Fatalf("rehashed = %v, want %v", rehashed, tt.rehashed)
+			}
+			if !tt.rehashed {
+				return
+			}
+			if !strings.HasPrefix(stored.Password, argonPrefix) {
+				t.Errorf("stored %q, want argon2id hash", stored.Password)
+			}
+			if ok, rehash, err := verifyPassword(stored.Password, testPassword); !ok || rehash || err != nil {
+				t.Errorf("new hash: ok %v, rehash %v, err %v", ok, rehash, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"strconv"
+	"t03/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRatingHistorySize = 20
+	maxRatingHistorySize     = 100
+)
+
+// rated сообщает, меняет ли партия рейтинги: только завершённые партии двух людей
+// с результатом. Партия, брошенная до подключения соперника, не в счёт.
+func rated(game *domain.Game) bool {
+	if game.Mode == domain.PVE || game.Player_X == uuid.Nil || game.Player_O == uuid.Nil {
+		return false
+	}
+	switch game.State {
+	case domain.StatusDraw:
+		return true
+	case domain.StatusWin, domain.StatusResigned, domain.StatusTimeout, domain.StatusAbandoned:
+		return game.WinnerPID != uuid.Nil
+	}
+	return false
+}
+
+func (svc *GameServiceImpl) rateGame(game *domain.Game) domain.RateFunc {
+	scoreX := 0.5
+	switch game.WinnerPID {
+	case game.Player_X:
+		scoreX = 1
+	case game.Player_O:
+		scoreX = 0
+	}
+	return func(x, o domain.Rating) (domain.Rating, domain.Rating) {
+		return domain.RateGame(x, o, scoreX, game.UpdatedAt, svc.config.RatingPeriod)
+	}
+}
+
+func (svc *GameServiceImpl) GetRating(playerID string) (*domain.Rating, error) {
+	id, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := svc.repo.GetRating(id)
+	if err != nil {
+		return nil, err
+	}
+	decayed := rating.Decayed(time.Now().UTC(), svc.config.RatingPeriod)
+	return &decayed, nil
+}
+
+func (svc *GameServiceImpl) GetRatingHistory(playerID string, limit int) ([]domain.RatingChange, error) {
+	id, err := parsePlayerID(playerID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRatingHistorySize
+	}
+	if limit > maxRatingHistorySize {
+		return nil, domain.NewError(domain.ErrInvalidInput, "limit must not exceed "+strconv.Itoa(maxRatingHistorySize)).With("max", maxRatingHistorySize)
+	}
+	return svc.repo.GetRatingHistory(id, limit)
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// schedule запускает job раз в interval, пока приложение работает.
+// Нулевой interval отключает задачу.
+func schedule(lc fx.Lifecycle, interval time.Duration, job func()) {
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						job()
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
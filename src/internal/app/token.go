@@ -0,0 +1,87 @@
+package app
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"t03/internal/domain"
+)
+
+const (
+	tokenIssuer      = "t03"
+	refreshTokenSize = 32
+)
+
+type TokenConfig struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// NewTokenConfig требует общий секрет: со случайным токены перестают действовать
+// после перезапуска и не принимаются другими экземплярами сервера.
+func NewTokenConfig(jwtSecret string, allowRandomSecret bool, accessTTL, refreshTTL time.Duration) (TokenConfig, error) {
+	secret := []byte(jwtSecret)
+	if len(secret) == 0 {
+		if !allowRandomSecret {
+			return TokenConfig{}, errors.New("JWT_SECRET is not set; set JWT_ALLOW_RANDOM_SECRET=true to use a random secret in development")
+		}
+		log.Println("JWT_SECRET is not set, using a random secret")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return TokenConfig{}, err
+		}
+	}
+	return TokenConfig{
+		Secret:     secret,
+		AccessTTL:  accessTTL,
+		RefreshTTL: refreshTTL,
+	}, nil
+}
+
+func (s *UserServiceImpl) issueAccessToken(userID uuid.UUID, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.tokens.AccessTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   userID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	signed, err := token.SignedString(s.tokens.Secret)
+	return signed, expiresAt, err
+}
+
+func (s *UserServiceImpl) parseAccessToken(raw string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (any, error) {
+		return s.tokens.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid access token")
+	}
+	if _, err = uuid.Parse(claims.Subject); err != nil {
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid access token")
+	}
+	return claims.Subject, nil
+}
+
+func newRefreshToken() (string, error) {
+	buf := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// В базе лежит только хеш refresh-токена, сам токен знает лишь клиент.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"errors"
+	"t03/internal/domain"
+	"t03/internal/infra/inmem"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestUserService(repo domain.GameRepository, refreshTTL time.Duration) *UserServiceImpl {
+	tokens := TokenConfig{Secret: []byte("test secret"), AccessTTL: time.Minute, RefreshTTL: refreshTTL}
+	return NewUserService(repo, tokens).(*UserServiceImpl)
+}
+
+func mustIssue(t *testing.T, svc *UserServiceImpl, userID uuid.UUID) *domain.TokenPair {
+	t.Helper()
+	pair, err := svc.issueTokens(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair
+}
+
+func wantUnauthorized(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("got %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	svc := newTestUserService(inmem.NewGameRepository(), time.Hour)
+	userID := uuid.New()
+	first := mustIssue(t, svc, userID)
+
+	second, err := svc.RefreshTokens(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	if subject, err := svc.parseAccessToken(second.AccessToken); err != nil || subject != userID.String() {
+		t.Errorf("new access token: %q, %v", subject, err)
+	}
+
+	third, err := svc.RefreshTokens(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("rotated token rejected: %v", err)
+	}
+	if third.RefreshToken == second.RefreshToken {
+		t.Error("refresh token was not rotated twice")
+	}
+}
+
+func TestRefreshTokenReuseRevokesAllSessions(t *testing.T) {
+	svc := newTestUserService(inmem.NewGameRepository(), time.Hour)
+	userID, otherID := uuid.New(), uuid.New()
+	stolen := mustIssue(t, svc, userID)
+	otherSession := mustIssue(t, svc, userID)
+	otherUser := mustIssue(t, svc, otherID)
+
+	rotated, err := svc.RefreshTokens(stolen.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.RefreshTokens(stolen.RefreshToken)
+	wantUnauthorized(t, err)
+
+	for name, token := range map[string]string{"rotated": rotated.RefreshToken, "other session": otherSession.RefreshToken} {
+		_, err = svc.RefreshTokens(token)
+		if !errors.Is(err, domain.ErrUnauthorized) {
+			t.Errorf("%s token after reuse: got %v, want ErrUnauthorized", name, err)
+		}
+	}
+	if _, err = svc.RefreshTokens(otherUser.RefreshToken); err != nil {
+		t.Errorf("other user's session revoked: %v", err)
+	}
+}
+
+// racingRepo имитирует параллельный запрос, который успевает использовать
+// refresh-токен между его чтением и отзывом.
+type racingRepo struct {
+	domain.GameRepository
+}
+
+func (r racingRepo) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	token, err := r.GameRepository.GetRefreshToken(tokenHash)
+	if err == nil {
+		_, err = r.GameRepository.RevokeRefreshToken(tokenHash)
+	}
+	return token, err
+}
+
+func TestRefreshTokenConcurrentReuse(t *testing.T) {
+	repo := inmem.NewGameRepository()
+	svc := newTestUserService(repo, time.Hour)
+	userID := uuid.New()
+	other := mustIssue(t, svc, userID)
+	pair := mustIssue(t, svc, userID)
+
+	svc.repo = racingRepo{repo}
+	_, err := svc.RefreshTokens(pair.RefreshToken)
+	wantUnauthorized(t, err)
+
+	svc.repo = repo
+	_, err = svc.RefreshTokens(other.RefreshToken)
+	wantUnauthorized(t, err)
+}
+
+func TestRefreshTokenRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		refreshTTL time.Duration
+		token      func(t *testing.T, svc *UserServiceImpl) string
+	}{
+		{"unknown", time.Hour, func(t *testing.T, svc *UserServiceImpl) string {
+			return "not a token"
+		}},
+		{"expired", -time.Second, func(t *testing.T, svc *UserServiceImpl) string {
+			return mustIssue(t, svc, uuid.New()).RefreshToken
+		}},
+		{"signed out", time.Hour, func(t *testing.T, svc *UserServiceImpl) string {
+			token := mustIssue(t, svc, uuid.New()).RefreshToken
+			if err := svc.SignOut(token); err != nil {
+				t.Fatal(err)
+			}
+			return token
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestUserService(inmem.NewGameRepository(), tt.refreshTTL)
+			_, err := svc.RefreshTokens(tt.token(t, svc))
+			wantUnauthorized(t, err)
+		})
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	svc := newTestUserService(inmem.NewGameRepository(), time.Hour)
+	userID := uuid.New()
+	now := time.Now()
+
+	valid, _, err := svc.issueAccessToken(userID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, err := svc.parseAccessToken(valid); err != nil || subject != userID.String() {
+		t.Errorf("valid token: %q, %v", subject, err)
+	}
+
+	expired, _, err := svc.issueAccessToken(userID, now.Add(-2*svc.tokens.AccessTTL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.parseAccessToken(expired)
+	wantUnauthorized(t, err)
+
+	other := newTestUserService(inmem.NewGameRepository(), time.Hour)
+	other.tokens.Secret = []byte("another secret")
+	foreign, _, err := other.issueAccessToken(userID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.parseAccessToken(foreign)
+	wantUnauthorized(t, err)
+}
+
+func TestNewTokenConfig(t *testing.T) {
+	if _, err := NewTokenConfig("", false, time.Minute, time.Hour); err == nil {
+		t.Error("empty secret accepted")
+	}
+	config, err := NewTokenConfig("", true, time.Minute, time.Hour)
+	if err != nil || len(config.Secret) != 32 {
+		t.Errorf("random secret: %d bytes, %v", len(config.Secret), err)
+	}
+	config, err = NewTokenConfig("secret", false, time.Minute, time.Hour)
+	if err != nil || string(config.Secret) != "secret" {
+		t.Errorf("configured secret: %q, %v", config.Secret, err)
+	}
+}
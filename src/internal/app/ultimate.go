@@ -0,0 +1,59 @@
+package app
+
+import (
+	"strconv"
+	"t03/internal/domain"
+)
+
+func validateUltimateMove(game *domain.Game, row, col int) error {
+	if game.Ultimate == nil {
+		return nil
+	}
+	index := domain.SubBoardIndex(row, col)
+	if game.Ultimate.SubBoards[index].State != domain.StatusTurn {
+		return domain.NewError(domain.ErrIllegalMove, "sub-board "+strconv.Itoa(index)+" is already finished").With("subBoard", index)
+	}
+	if game.Ultimate.ActiveSubBoard != domain.AnySubBoard && game.Ultimate.ActiveSubBoard != index {
+		return domain.NewError(domain.ErrIllegalMove, "you must play in sub-board "+strconv.Itoa(game.Ultimate.ActiveSubBoard)).
+			With("activeSubBoard", game.Ultimate.ActiveSubBoard)
+	}
+	return nil
+}
+
+func updateUltimate(game *domain.Game, row, col int) {
+	if game.Ultimate == nil {
+		return
+	}
+	index := domain.SubBoardIndex(row, col)
+	over, who := domain.CheckGameOver(game.Board.SubBoard(index), domain.SubBoardSize)
+	if over {
+		if who == domain.Empty {
+			game.Ultimate.SubBoards[index] = domain.SubBoard{State: domain.StatusDraw}
+		} else {
+			game.Ultimate.SubBoards[index] = domain.SubBoard{State: domain.StatusWin, Winner: who}
+		}
+	}
+
+	// Клетка внутри малой доски указывает, на какой доске ходит соперник.
+	next := (row%domain.SubBoardSize)*domain.SubBoardSize + col%domain.SubBoardSize
+	if game.Ultimate.SubBoards[next].State != domain.StatusTurn {
+		next = domain.AnySubBoard
+	}
+	game.Ultimate.ActiveSubBoard = next
+}
+
+func checkUltimateGameOver(ultimate *domain.UltimateBoard) (bool, domain.Cell) {
+	meta := domain.NewBoard(domain.SubBoardSize, domain.SubBoardSize)
+	finished := 0
+	for i, sub := range ultimate.SubBoards {
+		if sub.State != domain.StatusTurn {
+			finished++
+		}
+		meta[i/domain.SubBoardSize][i%domain.SubBoardSize] = sub.Winner
+	}
+
+	if over, who := domain.CheckGameOver(meta, domain.SubBoardSize); over && who != domain.Empty {
+		return true, who
+	}
+	return finished == domain.SubBoardsCount, domain.Empty
+}
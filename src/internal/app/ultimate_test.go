@@ -0,0 +1,154 @@
+package app
+
+import (
+	"errors"
+	"t03/internal/domain"
+	"testing"
+)
+
+type cell struct {
+	row, col int
+	symbol   domain.Cell
+}
+
+func ultimateGame(cells ...cell) *domain.Game {
+	game := &domain.Game{
+		Mode:     domain.ULTIMATE,
+		Board:    domain.NewBoard(domain.UltimateBoardSize, domain.UltimateBoardSize),
+		Ultimate: domain.NewUltimateBoard(),
+	}
+	for _, c := range cells {
+		game.Board[c.row][c.col] = c.symbol
+	}
+	return game
+}
+
+func finished(states map[int]domain.SubBoard) *domain.UltimateBoard {
+	ultimate := domain.NewUltimateBoard()
+	for i, sub := range states {
+		ultimate.SubBoards[i] = sub
+	}
+	return ultimate
+}
+
+var (
+	wonByX = domain.SubBoard{State: domain.StatusWin, Winner: domain.X}
+	wonByO = domain.SubBoard{State: domain.StatusWin, Winner: domain.O}
+	drawn  = domain.SubBoard{State: domain.StatusDraw}
+)
+
+func TestValidateUltimateMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		active   int
+		finished map[int]domain.SubBoard
+		row, col int
+		legal    bool
+	}{
+		{"any sub-board", domain.AnySubBoard, nil, 8, 8, true},
+		{"active sub-board", 4, nil, 4, 5, true},
+		{"other sub-board", 4, nil, 0, 0, false},
+		{"won sub-board", domain.AnySubBoard, map[int]domain.SubBoard{0: wonByX}, 1, 1, false},
+		{"drawn sub-board", domain.AnySubBoard, map[int]domain.SubBoard{8: drawn}, 6, 6, false},
+		{"free move next to finished sub-board", domain.AnySubBoard, map[int]domain.SubBoard{0: wonByO}, 0, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := ultimateGame()
+			game.Ultimate = finished(tt.finished)
+			game.Ultimate.ActiveSubBoard = tt.active
+			err := validateUltimateMove(game, tt.row, tt.col)
+			if tt.legal && err != nil {
+				t.Errorf("got %v, want legal move", err)
+			}
+			if !tt.legal && !errors.Is(err, domain.ErrIllegalMove) {
+				t.Errorf("got %v, want ErrIllegalMove", err)
+			}
+		})
+	}
+}
+
+func TestValidateUltimateMoveIgnoresClassicGames(t *testing.T) {
+	game := &domain.Game{Board: domain.NewBoard(3, 3)}
+	if err := validateUltimateMove(game, 2, 2); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestUpdateUltimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cells    []cell
+		finished map[int]domain.SubBoard
+		move     cell
+		active   int
+		sub      domain.SubBoard // итог малой доски, на которой сделан ход
+	}{
+		{"routes by cell inside sub-board", nil, nil, cell{1, 5, domain.X}, 5, domain.SubBoard{State: domain.StatusTurn}},
+		{"routes to the same sub-board", nil, nil, cell{4, 4, domain.O}, 4, domain.SubBoard{State: domain.StatusTurn}},
+		{"target won gives free move", nil, map[int]domain.SubBoard{5: wonByO}, cell{1, 5, domain.X}, domain.AnySubBoard, domain.SubBoard{State: domain.StatusTurn}},
+		{"target drawn gives free move", nil, map[int]domain.SubBoard{5: drawn}, cell{1, 5, domain.X}, domain.AnySubBoard, domain.SubBoard{State: domain.StatusTurn}},
+		{"wins sub-board", []cell{{0, 0, domain.X}, {0, 1, domain.X}}, nil, cell{0, 2, domain.X}, 2, wonByX},
+		{"wins the targeted sub-board", []cell{{1, 1, domain.O}, {2, 2, domain.O}}, nil, cell{0, 0, domain.O}, domain.AnySubBoard, wonByO},
+		{"draws sub-board", []cell{
+			{0, 0, domain.X}, {0, 1, domain.O}, {0, 2, domain.X},
+			{1, 0, domain.X}, {1, 1, domain.O}, {1, 2, domain.O},
+			{2, 0, domain.O}, {2, 1, domain.X},
+		}, nil, cell{2, 2, domain.X}, 8, drawn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := ultimateGame(tt.cells...)
+			game.Ultimate = finished(tt.finished)
+			applyMove(game, tt.move.row, tt.move.col, tt.move.symbol)
+
+			if game.Ultimate.ActiveSubBoard != tt.active {
+				t.Errorf("active sub-board = %d, want %d", game.Ultimate.ActiveSubBoard, tt.active)
+			}
+			index := domain.SubBoardIndex(tt.move.row, tt.move.col)
+			if got := game.Ultimate.SubBoards[index]; got != tt.sub {
+				t.Errorf("sub-board %d = %+v, want %+v", index, got, tt.sub)
+			}
+		})
+	}
+}
+
+func TestCheckUltimateGameOver(t *testing.T) {
+	allDrawn := make(map[int]domain.SubBoard, domain.SubBoardsCount)
+	for i := 0; i < domain.SubBoardsCount; i++ {
+		allDrawn[i] = drawn
+	}
+
+	tests := []struct {
+		name     string
+		finished map[int]domain.SubBoard
+		over     bool
+		winner   domain.Cell
+	}{
+		{"new game", nil, false, domain.Empty},
+		{"row of sub-boards", map[int]domain.SubBoard{0: wonByX, 1: wonByX, 2: wonByX}, true, domain.X},
+		{"column of sub-boards", map[int]domain.SubBoard{1: wonByO, 4: wonByO, 7: wonByO}, true, domain.O},
+		{"diagonal of sub-boards", map[int]domain.SubBoard{2: wonByO, 4: wonByO, 6: wonByO, 0: wonByX}, true, domain.O},
+		{"drawn sub-board breaks the line", map[int]domain.SubBoard{0: wonByX, 1: drawn, 2: wonByX}, false, domain.Empty},
+		{"mixed line", map[int]domain.SubBoard{0: wonByX, 1: wonByO, 2: wonByX}, false, domain.Empty},
+		{"all sub-boards drawn", allDrawn, true, domain.Empty},
+		{"all finished without a line", map[int]domain.SubBoard{
+			0: wonByX, 1: wonByO, 2: wonByX,
+			3: wonByX, 4: wonByO, 5: wonByO,
+			6: wonByO, 7: wonByX, 8: drawn,
+		}, true, domain.Empty},
+		{"one sub-board left", map[int]domain.SubBoard{
+			0: wonByX, 1: wonByO, 2: wonByX,
+			3: wonByX, 4: wonByO, 5: wonByO,
+			6: wonByO, 7: wonByX,
+		}, false, domain.Empty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			over, winner := checkUltimateGameOver(finished(tt.finished))
+			if over != tt.over || winner != tt.winner {
+				t.Errorf("got %v, %d, want %v, %d", over, winner, tt.over, tt.winner)
+			}
+		})
+	}
+}
@@ -4,50 +4,163 @@ import (
 	"encoding/base64"
 	"errors"
 	"github.com/google/uuid"
+	"log"
 	"strings"
 	"t03/internal/api/dto"
 	"t03/internal/domain"
+	"time"
 )
 
+var dummyHash, _ = hashPassword("dummy password")
+
 type UserServiceImpl struct {
-	repo domain.GameRepository
+	repo   domain.GameRepository
+	tokens TokenConfig
 }
 
-func NewUserService(repo domain.GameRepository) domain.UserService {
-	return &UserServiceImpl{repo: repo}
+func NewUserService(repo domain.GameRepository, tokens TokenConfig) domain.UserService {
+	return &UserServiceImpl{repo: repo, tokens: tokens}
 }
 
 func (s *UserServiceImpl) Register(request dto.SignUpRequest) (string, error) {
+	if request.Login == "" || request.Password == "" {
+		return "", domain.NewError(domain.ErrInvalidInput, "login and password are required")
+	}
 	_, err := s.repo.GetUser(request.Login)
 	if err == nil {
-		return "", errors.New("user already exists")
+		return "", domain.NewError(domain.ErrConflict, "user already exists")
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	hash, err := hashPassword(request.Password)
+	if err != nil {
+		return "", err
 	}
 	id := uuid.New()
 	user := &domain.User{
 		ID:       id,
 		Login:    request.Login,
-		Password: request.Password,
+		Password: hash,
 	}
 	return id.String(), s.repo.SaveUser(user)
 }
 
 func (s *UserServiceImpl) AuthenticateBasic(encoded string) (string, error) {
 	if !strings.HasPrefix(encoded, "Basic ") {
-		return "", errors.New("invalid format")
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid authorization format")
 	}
 
 	trimed := strings.TrimPrefix(encoded, "Basic ")
 	data, err := base64.StdEncoding.DecodeString(trimed)
 	if err != nil {
-		return "", err
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid authorization format")
 	}
 	parts := strings.SplitN(string(data), ":", 2)
 	if len(parts) != 2 {
-		return "", errors.New("invalid format")
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid authorization format")
 	}
 	user, err := s.repo.GetUser(parts[0])
-	if err != nil || user.Password != parts[1] {
-		return "", errors.New("invalid login or password")
+	if err != nil {
+		// Считаем хеш и для несуществующего логина, чтобы время ответа его не выдавало.
+		verifyPassword(dummyHash, parts[1])
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid login or password")
+	}
+	ok, rehash, err := verifyPassword(user.Password, parts[1])
+	if err != nil || !ok {
+		return "", domain.NewError(domain.ErrUnauthorized, "invalid login or password")
+	}
+	if rehash {
+		if hash, err := hashPassword(parts[1]); err == nil {
+			if err = s.repo.UpdateUserPassword(user.ID, hash); err != nil {
+				log.Printf("failed to rehash password for user %s: %v", user.ID, err)
+			}
+		}
 	}
 	return user.ID.String(), nil
 }
+
+func (s *UserServiceImpl) Authenticate(authorization string) (string, error) {
+	if raw, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return s.parseAccessToken(raw)
+	}
+	return s.AuthenticateBasic(authorization)
+}
+
+func (s *UserServiceImpl) SignIn(encoded string) (string, *domain.TokenPair, error) {
+	userID, err := s.AuthenticateBasic(encoded)
+	if err != nil {
+		return "", nil, err
+	}
+	tokens, err := s.issueTokens(uuid.MustParse(userID))
+	if err != nil {
+		return "", nil, err
+	}
+	return userID, tokens, nil
+}
+
+func (s *UserServiceImpl) RefreshTokens(refreshToken string) (*domain.TokenPair, error) {
+	stored, err := s.repo.GetRefreshToken(hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, domain.NewError(domain.ErrUnauthorized, "invalid refresh token")
+	}
+	if stored.Revoked {
+		// Повторное предъявление уже использованного токена — признак кражи,
+		// поэтому отзываем все сессии пользователя.
+		if err = s.repo.RevokeUserRefreshTokens(stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewError(domain.ErrUnauthorized, "refresh token has been revoked")
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, domain.NewError(domain.ErrUnauthorized, "refresh token has expired")
+	}
+
+	// Токен мог успеть использовать параллельный запрос — тогда это тоже повтор.
+	revoked, err := s.repo.RevokeRefreshToken(stored.TokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		if err = s.repo.RevokeUserRefreshTokens(stored.UserID); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewError(domain.ErrUnauthorized, "refresh token has been revoked")
+	}
+	return s.issueTokens(stored.UserID)
+}
+
+func (s *UserServiceImpl) SignOut(refreshToken string) error {
+	_, err := s.repo.RevokeRefreshToken(hashRefreshToken(refreshToken))
+	return err
+}
+
+func (s *UserServiceImpl) issueTokens(userID uuid.UUID) (*domain.TokenPair, error) {
+	now := time.Now().UTC()
+	access, accessExpiresAt, err := s.issueAccessToken(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: hashRefreshToken(refresh),
+		ExpiresAt: now.Add(s.tokens.RefreshTTL),
+		CreatedAt: now,
+	}
+	if err = s.repo.SaveRefreshToken(stored); err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, nil
+}
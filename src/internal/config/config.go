@@ -0,0 +1,276 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Storage  StorageConfig  `yaml:"storage" toml:"storage"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Game     GameConfig     `yaml:"game" toml:"game"`
+	Features FeaturesConfig `yaml:"features" toml:"features"`
+}
+
+type ServerConfig struct {
+	Addr           string `yaml:"addr" toml:"addr"`
+	StaticDir      string `yaml:"static_dir" toml:"static_dir"`
+	AllowedOrigins string `yaml:"allowed_origins" toml:"allowed_origins"` // через запятую
+}
+
+type StorageConfig struct {
+	Driver       string         `yaml:"driver" toml:"driver"`
+	QueryTimeout time.Duration  `yaml:"query_timeout" toml:"query_timeout"`
+	TxTimeout    time.Duration  `yaml:"tx_timeout" toml:"tx_timeout"`
+	Postgres     PostgresConfig `yaml:"postgres" toml:"postgres"`
+	SQLite       SQLiteConfig   `yaml:"sqlite" toml:"sqlite"`
+}
+
+type PostgresConfig struct {
+	DSN      string `yaml:"dsn" toml:"dsn"`
+	MaxConns int32  `yaml:"max_conns" toml:"max_conns"`
+	MinConns int32  `yaml:"min_conns" toml:"min_conns"`
+}
+
+type SQLiteConfig struct {
+	Path string `yaml:"path" toml:"path"`
+}
+
+type AuthConfig struct {
+	JWTSecret  string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	AccessTTL  time.Duration `yaml:"access_ttl" toml:"access_ttl"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" toml:"refresh_ttl"`
+	// Только для разработки: без jwt_secret сессии не переживают перезапуск.
+	AllowRandomSecret bool `yaml:"allow_random_secret" toml:"allow_random_secret"`
+}
+
+type GameConfig struct {
+	AIMoveBudget       time.Duration `yaml:"ai_move_budget" toml:"ai_move_budget"`
+	ClockSweepInterval time.Duration `yaml:"clock_sweep_interval" toml:"clock_sweep_interval"`
+	WaitingTTL         time.Duration `yaml:"waiting_ttl" toml:"waiting_ttl"`
+	InactivityTimeout  time.Duration `yaml:"inactivity_timeout" toml:"inactivity_timeout"`
+	AbandonInterval    time.Duration `yaml:"abandon_interval" toml:"abandon_interval"`
+	RatingPeriod       time.Duration `yaml:"rating_period" toml:"rating_period"`
+}
+
+type FeaturesConfig struct {
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+	Signup      bool `yaml:"signup" toml:"signup"`
+	StaticFiles bool `yaml:"static_files" toml:"static_files"`
+}
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:      ":8080",
+			StaticDir: "static",
+		},
+		Storage: StorageConfig{
+			Driver:       "postgres",
+			QueryTimeout: 100 * time.Millisecond,
+			TxTimeout:    2 * time.Second,
+			Postgres: PostgresConfig{
+				DSN: "postgres://postgres@localhost:5432/tic_tac_toe",
+			},
+			SQLite: SQLiteConfig{
+				Path: "tic_tac_toe.db",
+			},
+		},
+		Auth: AuthConfig{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 30 * 24 * time.Hour,
+		},
+		Game: GameConfig{
+			AIMoveBudget:       300 * time.Millisecond,
+			ClockSweepInterval: time.Second,
+			WaitingTTL:         24 * time.Hour,
+			InactivityTimeout:  72 * time.Hour,
+			AbandonInterval:    time.Minute,
+			RatingPeriod:       24 * time.Hour,
+		},
+		Features: FeaturesConfig{
+			AutoMigrate: true,
+			Signup:      true,
+			StaticFiles: true,
+		},
+	}
+}
+
+// Load собирает конфигурацию по возрастанию приоритета: значения по умолчанию,
+// файл (-config или CONFIG_FILE), переменные окружения, флаги командной строки.
+// Возвращает позиционные аргументы, оставшиеся после флагов.
+func Load(args []string) (Config, []string, error) {
+	cfg := Default()
+
+	path := configPath(args)
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, nil, err
+		}
+	}
+
+	options := cfg.options()
+	for _, opt := range options {
+		if value, ok := os.LookupEnv(opt.env); ok {
+			if err := set(opt.target, value); err != nil {
+				return cfg, nil, fmt.Errorf("%s: %w", opt.env, err)
+			}
+		}
+	}
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.String("config", path, "path to a YAML or TOML config file")
+	for _, opt := range options {
+		fs.Var(&flagValue{opt.target}, opt.flag, opt.usage+" (env "+opt.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+type option struct {
+	env    string
+	flag   string
+	usage  string
+	target any
+}
+
+func (c *Config) options() []option {
+	return []option{
+		{"LISTEN_ADDR", "addr", "HTTP listen address", &c.Server.Addr},
+		{"STATIC_DIR", "static-dir", "directory with static files", &c.Server.StaticDir},
+		{"ALLOWED_ORIGINS", "allowed-origins", "comma-separated origins besides the server's own allowed to open game WebSockets", &c.Server.AllowedOrigins},
+		{"STORAGE_DRIVER", "storage", "storage driver: postgres, sqlite or memory", &c.Storage.Driver},
+		{"DB_QUERY_TIMEOUT", "query-timeout", "database query timeout", &c.Storage.QueryTimeout},
+		{"DB_TX_TIMEOUT", "tx-timeout", "timeout of a whole database transaction, including waits for row locks", &c.Storage.TxTimeout},
+		{"DATABASE_URL", "dsn", "PostgreSQL connection string", &c.Storage.Postgres.DSN},
+		{"DB_MAX_CONNS", "db-max-conns", "PostgreSQL pool max connections, 0 for driver default", &c.Storage.Postgres.MaxConns},
+		{"DB_MIN_CONNS", "db-min-conns", "PostgreSQL pool min connections", &c.Storage.Postgres.MinConns},
+		{"SQLITE_PATH", "sqlite-path", "SQLite database file", &c.Storage.SQLite.Path},
+		{"JWT_SECRET", "jwt-secret", "secret for signing access tokens", &c.Auth.JWTSecret},
+		{"JWT_ACCESS_TTL", "access-ttl", "access token lifetime", &c.Auth.AccessTTL},
+		{"JWT_REFRESH_TTL", "refresh-ttl", "refresh token lifetime", &c.Auth.RefreshTTL},
+		{"JWT_ALLOW_RANDOM_SECRET", "allow-random-secret", "sign tokens with a random secret when JWT_SECRET is empty (development only)", &c.Auth.AllowRandomSecret},
+		{"AI_MOVE_BUDGET", "ai-move-budget", "time budget for a single AI move", &c.Game.AIMoveBudget},
+		{"CLOCK_SWEEP_INTERVAL", "clock-sweep-interval", "how often games with expired clocks are finished, 0 to disable", &c.Game.ClockSweepInterval},
+		{"WAITING_GAME_TTL", "waiting-ttl", "how long a game waits for an opponent before it is abandoned, 0 to keep forever", &c.Game.WaitingTTL},
+		{"INACTIVE_GAME_TIMEOUT", "inactivity-timeout", "how long a player may not move before forfeiting, 0 to wait forever", &c.Game.InactivityTimeout},
+		{"ABANDON_INTERVAL", "abandon-interval", "how often stale games are abandoned, 0 to disable", &c.Game.AbandonInterval},
+		{"RATING_PERIOD", "rating-period", "rating period after which an idle player's rating deviation grows, 0 to disable", &c.Game.RatingPeriod},
+		{"FEATURE_AUTO_MIGRATE", "auto-migrate", "apply database migrations on startup", &c.Features.AutoMigrate},
+		{"FEATURE_SIGNUP", "signup", "allow registration of new users", &c.Features.Signup},
+		{"FEATURE_STATIC_FILES", "static-files", "serve the web client", &c.Features.StaticFiles},
+	}
+}
+
+// Путь к файлу нужен до разбора остальных флагов, поэтому ищем его вручную.
+func configPath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv("CONFIG_FILE")
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func set(target any, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*t = v
+	case *int32:
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return err
+		}
+		*t = int32(v)
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*t = v
+	default:
+		return fmt.Errorf("unsupported option type %T", target)
+	}
+	return nil
+}
+
+type flagValue struct {
+	target any
+}
+
+func (f *flagValue) String() string {
+	if f.target == nil {
+		return ""
+	}
+	return fmt.Sprint(deref(f.target))
+}
+
+func (f *flagValue) Set(value string) error {
+	return set(f.target, value)
+}
+
+// Позволяет писать -signup вместо -signup=true.
+func (f *flagValue) IsBoolFlag() bool {
+	_, ok := f.target.(*bool)
+	return ok
+}
+
+func deref(target any) any {
+	switch t := target.(type) {
+	case *string:
+		return *t
+	case *bool:
+		return *t
+	case *int32:
+		return *t
+	case *time.Duration:
+		return *t
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package di
+
+import (
+	"strings"
+
+	handler "t03/internal/api/http"
+	"t03/internal/app"
+	"t03/internal/config"
+	"t03/internal/infra/memory"
+	"t03/internal/infra/sqlite"
+)
+
+func newStorageConfig(cfg config.Config) StorageConfig {
+	return StorageConfig{Driver: cfg.Storage.Driver}
+}
+
+func newPGConfig(cfg config.Config) memory.Config {
+	return memory.Config{
+		DSN:          cfg.Storage.Postgres.DSN,
+		MaxConns:     cfg.Storage.Postgres.MaxConns,
+		MinConns:     cfg.Storage.Postgres.MinConns,
+		QueryTimeout: cfg.Storage.QueryTimeout,
+		TxTimeout:    cfg.Storage.TxTimeout,
+		AutoMigrate:  cfg.Features.AutoMigrate,
+	}
+}
+
+func newSQLiteConfig(cfg config.Config) sqlite.Config {
+	return sqlite.Config{
+		Path:         cfg.Storage.SQLite.Path,
+		QueryTimeout: cfg.Storage.QueryTimeout,
+		TxTimeout:    cfg.Storage.TxTimeout,
+		AutoMigrate:  cfg.Features.AutoMigrate,
+	}
+}
+
+func newTokenConfig(cfg config.Config) (app.TokenConfig, error) {
+	return app.NewTokenConfig(cfg.Auth.JWTSecret, cfg.Auth.AllowRandomSecret, cfg.Auth.AccessTTL, cfg.Auth.RefreshTTL)
+}
+
+func newGameConfig(cfg config.Config) app.GameConfig {
+	return app.GameConfig{
+		AIMoveBudget:       cfg.Game.AIMoveBudget,
+		ClockSweepInterval: cfg.Game.ClockSweepInterval,
+		WaitingTTL:         cfg.Game.WaitingTTL,
+		InactivityTimeout:  cfg.Game.InactivityTimeout,
+		AbandonInterval:    cfg.Game.AbandonInterval,
+		RatingPeriod:       cfg.Game.RatingPeriod,
+	}
+}
+
+func newServerConfig(cfg config.Config) handler.ServerConfig {
+	return handler.ServerConfig{
+		Addr:           cfg.Server.Addr,
+		StaticDir:      cfg.Server.StaticDir,
+		ServeStatic:    cfg.Features.StaticFiles,
+		AllowSignup:    cfg.Features.Signup,
+		AllowedOrigins: splitList(cfg.Server.AllowedOrigins),
+	}
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -5,16 +5,33 @@ import (
 	"os"
 	handler "t03/internal/api/http"
 	"t03/internal/app"
-	"t03/internal/infra/memory"
+	"t03/internal/domain"
+	"t03/internal/infra/ai"
+	"t03/internal/infra/events"
 )
 
 var Module = fx.Options(
 
-	fx.Provide(memory.NewPGConfig),
-	fx.Provide(memory.NewStorage),
-	fx.Provide(memory.NewGameRepository),
-	fx.Provide(app.NewGameService),
+	fx.Provide(
+		newStorageConfig,
+		newPGConfig,
+		newSQLiteConfig,
+		newTokenConfig,
+		newGameConfig,
+		newServerConfig,
+	),
+	fx.Provide(NewGameRepository),
+	fx.Provide(
+		aiEngine(ai.NewMinimax),
+		aiEngine(ai.NewShallowMinimax),
+		aiEngine(ai.NewHeuristic),
+		aiEngine(ai.NewMCTS),
+		aiEngine(ai.NewRandom),
+	),
+	fx.Provide(events.NewBroker),
+	fx.Provide(fx.Annotate(app.NewGameService, fx.ParamTags(``, ``, ``, `group:"ai_engines"`))),
 	fx.Provide(app.NewUserService),
+	fx.Provide(app.NewMatchmaker),
 	fx.Provide(handler.NewGameHandler),
 
 	fx.Invoke(func(g fx.DotGraph) {
@@ -24,5 +41,11 @@ var Module = fx.Options(
 		}
 	}),
 
+	fx.Invoke(app.RunClockSweeper),
+	fx.Invoke(app.RunAbandonSweeper),
 	fx.Invoke(handler.RegisterRoutes),
 )
+
+func aiEngine(constructor func() domain.AIEngine) any {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"ai_engines"`))
+}
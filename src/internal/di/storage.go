@@ -0,0 +1,69 @@
+package di
+
+import (
+	"fmt"
+
+	"go.uber.org/fx"
+	"t03/internal/config"
+	"t03/internal/domain"
+	"t03/internal/infra/inmem"
+	"t03/internal/infra/memory"
+	"t03/internal/infra/migrate"
+	"t03/internal/infra/sqlite"
+)
+
+type StorageConfig struct {
+	Driver string
+}
+
+func NewGameRepository(lc fx.Lifecycle, cfg StorageConfig, pg memory.Config, lite sqlite.Config) (domain.GameRepository, error) {
+	switch cfg.Driver {
+	case "memory":
+		return inmem.NewGameRepository(), nil
+	case "postgres":
+		storage, err := memory.NewStorage(lc, pg)
+		if err != nil {
+			return nil, err
+		}
+		return memory.NewGameRepository(storage), nil
+	case "sqlite":
+		storage, err := sqlite.NewStorage(lc, lite)
+		if err != nil {
+			return nil, err
+		}
+		return sqlite.NewGameRepository(storage), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// Мигратор для подкоманды migrate: открывает хранилище без fx, чтобы
+// не запускать автоматическое применение миграций при старте.
+func NewMigrator(cfg config.Config) (*migrate.Migrator, func(), error) {
+	switch cfg.Storage.Driver {
+	case "postgres":
+		pool, err := memory.Open(newPGConfig(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		migrator, err := memory.NewMigrator(pool)
+		if err != nil {
+			pool.Close()
+			return nil, nil, err
+		}
+		return migrator, pool.Close, nil
+	case "sqlite":
+		db, err := sqlite.Open(newSQLiteConfig(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		migrator, err := sqlite.NewMigrator(db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return migrator, func() { db.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("storage driver %q has no migrations", cfg.Storage.Driver)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "errors"
+
+// Категории ошибок. Конкретные ошибки создаются через NewError и
+// сравниваются с категорией через errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrNotYourTurn  = errors.New("not your turn")
+	ErrIllegalMove  = errors.New("illegal move")
+	ErrGameOver     = errors.New("game is over")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	// Партию изменил другой запрос между чтением и сохранением.
+	ErrConflict = errors.New("game was modified by another request, reload and retry")
+)
+
+type Error struct {
+	Kind    error
+	Message string
+	Details map[string]any
+}
+
+func NewError(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func (e *Error) With(key string, value any) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]any)
+	}
+	e.Details[key] = value
+	return e
+}
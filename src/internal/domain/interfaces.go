@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"t03/internal/api/dto"
 )
@@ -8,22 +10,70 @@ import (
 type GameService interface {
 	PlayerVsAi(game *Game, playerId string) (*Game, error)
 	PlayerMove(game *Game, playerId string) (*Game, error)
-	NewGame(playerID string, gameType string) (string, error)
-	GetAvailableGames(pid string) (*GamesList, error)
+	MakeMove(gameID, playerID string, row, col, moveNumber int) (*Game, error)
+	NewGame(playerID string, gameType string, options GameOptions) (string, error)
+	NewMatchedGame(firstID, secondID string, gameType string, options GameOptions) (*Game, error)
+	ListGames(playerID string, query GamesQuery) (*GamesPage, error)
 	ConnectToGame(gameId, userId string) (*Game, error)
 	GetPlayerStats(playerID string) (*Stats, error)
+	GetGame(gameID string) (*Game, error)
+	GetGameHistory(gameID string) ([]Move, error)
+	ReplayGame(gameID string, moveNumber int) (*Game, error)
+	Resign(gameID, playerID string) (*Game, error)
+	OfferDraw(gameID, playerID string) (*Game, error)
+	AcceptDraw(gameID, playerID string) (*Game, error)
+	DeclineDraw(gameID, playerID string) (*Game, error)
+	ExpireGames() (int, error)
+	AbandonGames() (int, error)
+	GetRating(playerID string) (*Rating, error)
+	GetRatingHistory(playerID string, limit int) ([]RatingChange, error)
+	GetLeaderboard(query LeaderboardQuery) (*Leaderboard, error)
+}
+
+type Matchmaker interface {
+	Join(playerID, mode string, options GameOptions, ratingBand float64) (*MatchTicket, error)
+	Leave(playerID string) error
+	Status(playerID string) (*MatchTicket, error)
 }
 
 type GameRepository interface {
 	SaveGame(game *Game) error
+	// SaveRatedGame сохраняет завершённую партию и новые рейтинги её участников в одной транзакции.
+	SaveRatedGame(game *Game, rate RateFunc) error
+	GetRating(playerID uuid.UUID) (*Rating, error)
+	GetRatingHistory(playerID uuid.UUID, limit int) ([]RatingChange, error)
 	GetGame(id string) (*Game, error)
-	GetAvailableGames(pid string) (*GamesList, error)
+	ListGames(query GamesQuery) (*GamesPage, error)
+	GetExpiredGames(now time.Time) (uuid.UUIDs, error)
+	GetStaleGames(state GameState, updatedBefore time.Time) (uuid.UUIDs, error)
 	SaveUser(user *User) error
 	GetUser(login string) (*User, error)
+	UpdateUserPassword(userID uuid.UUID, password string) error
+	SaveRefreshToken(token *RefreshToken) error
+	GetRefreshToken(tokenHash string) (*RefreshToken, error)
+	RevokeRefreshToken(tokenHash string) (bool, error)
+	RevokeUserRefreshTokens(userID uuid.UUID) error
 	GetPlayerStats(playerID uuid.UUID) (*Stats, error)
+	GetLeaderboard(query LeaderboardQuery) ([]LeaderboardEntry, error)
 }
 
 type UserService interface {
 	Register(request dto.SignUpRequest) (string, error)
 	AuthenticateBasic(base64Credentials string) (string, error)
+	Authenticate(authorization string) (string, error)
+	SignIn(base64Credentials string) (string, *TokenPair, error)
+	RefreshTokens(refreshToken string) (*TokenPair, error)
+	SignOut(refreshToken string) error
+}
+
+type AIEngine interface {
+	Name() string
+	BestMove(board Board, winLength int, symbol Cell, budget time.Duration) (AIMove, error)
+}
+
+type GameEvents interface {
+	Publish(event GameEvent)
+	Subscribe(gameID uuid.UUID) (<-chan GameEvent, func())
+	SubscribeLobby() (<-chan GameEvent, func())
+	SubscribePlayer(playerID uuid.UUID) (<-chan GameEvent, func())
 }
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type GameState int
 type Gametype int
@@ -8,6 +12,7 @@ type Gametype int
 const (
 	PVP Gametype = iota
 	PVE
+	ULTIMATE
 )
 
 const (
@@ -15,19 +20,106 @@ const (
 	StatusTurn
 	StatusDraw
 	StatusWin
-	// StatusBoardCorrupted
-	// St
+	StatusResigned // победитель — WinnerPID, второй игрок сдался
+	StatusTimeout  // победитель — WinnerPID, у второго игрока кончилось время
+	// Партию бросили: к ожидающей никто не подключился (победителя нет)
+	// либо игрок перестал ходить (победитель — WinnerPID).
+	StatusAbandoned
+)
+
+// Нулевое поле — соответствующего ограничения нет. PerMove ограничивает
+// каждый ход, Clock — суммарное время игрока, к которому после хода
+// добавляется Increment.
+type TimeControl struct {
+	PerMove   time.Duration
+	Clock     time.Duration
+	Increment time.Duration
+}
+
+type Difficulty int
+
+// DifficultyPerfect идёт первым: партии, созданные до выбора сложности,
+// играли против полного минимакса.
+const (
+	DifficultyPerfect Difficulty = iota
+	DifficultyMedium
+	DifficultyEasy
+	DifficultyRandom
 )
 
+// Evaluation — оценка позиции для ходящего движка в диапазоне [-1, 1].
+type AIMove struct {
+	Row        int
+	Col        int
+	Evaluation float64
+}
+
 type Game struct {
-	GameId     uuid.UUID
-	Mode       Gametype
-	Board      Board
-	Player_X   uuid.UUID
-	Player_O   uuid.UUID
-	State      GameState
-	CurrentPID uuid.UUID
-	WinnerPID  uuid.UUID
+	GameId        uuid.UUID
+	Mode          Gametype
+	Board         Board
+	WinLength     int
+	Difficulty    Difficulty
+	Engine        string
+	Player_X      uuid.UUID
+	Player_O      uuid.UUID
+	State         GameState
+	CurrentPID    uuid.UUID
+	WinnerPID     uuid.UUID
+	Moves         []Move
+	Ultimate      *UltimateBoard
+	DrawOfferedBy uuid.UUID // uuid.Nil — ничью никто не предлагал
+	TimeControl   TimeControl
+	ClockX        time.Duration // оставшееся время игроков при TimeControl.Clock > 0
+	ClockO        time.Duration
+	TurnStartedAt time.Time
+	TurnDeadline  time.Time // нулевое — ход не ограничен по времени
+	Version       int       // 0 — партия ещё не сохранена
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (g *Game) Clone() *Game {
+	clone := *g
+	clone.Board = g.Board.Clone()
+	clone.Moves = append([]Move(nil), g.Moves...)
+	if g.Ultimate != nil {
+		ultimate := *g.Ultimate
+		clone.Ultimate = &ultimate
+	}
+	return &clone
+}
+
+type EventType string
+
+const (
+	EventGameCreated  EventType = "game_created"
+	EventNewOpenGame  EventType = "new_open_game"
+	EventPlayerJoined EventType = "player_joined"
+	EventMoveMade     EventType = "move_made"
+	EventGameOver     EventType = "game_over"
+	EventResigned     EventType = "resigned"
+	EventDrawOffered  EventType = "draw_offered"
+	EventDrawDeclined EventType = "draw_declined"
+	EventDrawAccepted EventType = "draw_accepted"
+	EventTimeout      EventType = "timeout"
+	EventAbandoned    EventType = "abandoned"
+	EventMatchFound   EventType = "match_found"
+)
+
+type GameEvent struct {
+	Type EventType
+	Game *Game
+	At   time.Time
+}
+
+type Move struct {
+	Number   int
+	PlayerID uuid.UUID
+	Row      int
+	Col      int
+	Symbol   Cell
+	MadeAt   time.Time
 }
 
 type Cell int
@@ -38,10 +130,152 @@ const (
 	O
 )
 
-type Board [3][3]Cell
+const (
+	DefaultBoardSize = 3
+	MinBoardSize     = 3
+	MaxBoardSize     = 19
+	MaxWinLength     = 5
+)
+
+type Board [][]Cell
+
+func NewBoard(width, height int) Board {
+	board := make(Board, height)
+	for i := range board {
+		board[i] = make([]Cell, width)
+	}
+	return board
+}
+
+func (b Board) Width() int {
+	if len(b) == 0 {
+		return 0
+	}
+	return len(b[0])
+}
+
+func (b Board) Height() int {
+	return len(b)
+}
+
+func (b Board) Clone() Board {
+	board := make(Board, len(b))
+	for i := range b {
+		board[i] = append([]Cell(nil), b[i]...)
+	}
+	return board
+}
+
+const (
+	SubBoardSize      = 3
+	SubBoardsCount    = SubBoardSize * SubBoardSize
+	UltimateBoardSize = SubBoardSize * SubBoardSize
+	AnySubBoard       = -1
+)
+
+// Поле ultimate-игры хранится в Game.Board как обычная доска 9x9,
+// UltimateBoard описывает разбиение на малые доски и чей ход куда.
+type UltimateBoard struct {
+	ActiveSubBoard int
+	SubBoards      [SubBoardsCount]SubBoard
+}
+
+type SubBoard struct {
+	State  GameState
+	Winner Cell
+}
+
+func NewUltimateBoard() *UltimateBoard {
+	ultimate := &UltimateBoard{ActiveSubBoard: AnySubBoard}
+	for i := range ultimate.SubBoards {
+		ultimate.SubBoards[i].State = StatusTurn
+	}
+	return ultimate
+}
+
+func SubBoardIndex(row, col int) int {
+	return (row/SubBoardSize)*SubBoardSize + col/SubBoardSize
+}
+
+func (b Board) SubBoard(index int) Board {
+	top, left := (index/SubBoardSize)*SubBoardSize, (index%SubBoardSize)*SubBoardSize
+	sub := NewBoard(SubBoardSize, SubBoardSize)
+	for i := range sub {
+		copy(sub[i], b[top+i][left:left+SubBoardSize])
+	}
+	return sub
+}
+
+type GameOptions struct {
+	Width       int
+	Height      int
+	WinLength   int
+	Difficulty  Difficulty
+	Engine      string
+	Symbol      Cell // Empty — сторона выбирается случайно
+	TimeControl TimeControl
+}
+
+type MatchStatus int
+
+const (
+	MatchIdle MatchStatus = iota
+	MatchQueued
+	MatchFound
+)
+
+// MatchTicket — положение игрока в очереди подбора соперника.
+type MatchTicket struct {
+	PlayerID   uuid.UUID
+	Status     MatchStatus
+	Mode       string
+	Options    GameOptions
+	JoinedAt   time.Time
+	Rating     float64
+	RatingBand float64 // допустимая разница рейтингов с соперником, 0 — любой
+	Position   int     // с единицы, только для MatchQueued
+	QueueSize  int
+	GameID     uuid.UUID // только для MatchFound
+}
+
+// Пустой фильтр равносилен Mine+AI+Open: активные партии игрока и лобби.
+type GamesFilter struct {
+	Mine     bool // свои незавершённые партии против людей
+	Open     bool // чужие партии, ожидающие соперника
+	Finished bool // свои завершённые партии
+	AI       bool // свои незавершённые партии против ИИ
+}
 
-type GamesList struct {
-	Games uuid.UUIDs
+// Партии отдаются от новых к старым, курсор — последняя партия предыдущей страницы.
+type GamesCursor struct {
+	CreatedAt time.Time
+	GameID    uuid.UUID
+}
+
+type GamesQuery struct {
+	PlayerID uuid.UUID
+	Filter   GamesFilter
+	After    *GamesCursor
+	Limit    int
+}
+
+type GameSummary struct {
+	GameID     uuid.UUID
+	Mode       Gametype
+	State      GameState
+	Player_X   uuid.UUID
+	Player_O   uuid.UUID
+	LoginX     string
+	LoginO     string
+	CurrentPID uuid.UUID
+	WinnerPID  uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type GamesPage struct {
+	Games []GameSummary
+	Next  *GamesCursor // nil — страница последняя
 }
 
 type Stats struct {
@@ -49,5 +283,50 @@ type Stats struct {
 	Wins       int
 	Losses     int
 	Draws      int
+	Abandoned  int // проигрыши из-за того, что игрок бросил партию; входят в Losses
+	WinRatePct float64
+	Rating     Rating
+}
+
+type LeaderboardSort int
+
+const (
+	LeaderboardByRating LeaderboardSort = iota
+	LeaderboardByWins
+	LeaderboardByWinRate
+)
+
+type LeaderboardWindow int
+
+const (
+	LeaderboardAllTime LeaderboardWindow = iota
+	LeaderboardMonthly                   // с начала текущего календарного месяца
+	LeaderboardWeekly                    // с понедельника текущей недели
+)
+
+// В таблице учитываются только завершённые партии, окно — по времени завершения.
+type LeaderboardQuery struct {
+	Sort     LeaderboardSort
+	Modes    []Gametype // пусто — все режимы
+	Window   LeaderboardWindow
+	Since    time.Time // начало окна, заполняет сервис
+	MinGames int
+	Limit    int
+}
+
+type LeaderboardEntry struct {
+	Rank       int
+	PlayerID   uuid.UUID
+	Login      string
+	Games      int
+	Wins       int
+	Losses     int
+	Draws      int
 	WinRatePct float64
+	Rating     Rating
+}
+
+type Leaderboard struct {
+	Query   LeaderboardQuery
+	Entries []LeaderboardEntry
 }
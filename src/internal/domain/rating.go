@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Параметры Glicko-2 по статье Glickman: рейтинг новичка, его отклонение
+// и волатильность, ограничение изменения волатильности tau.
+const (
+	DefaultRating     = 1500.0
+	DefaultRD         = 350.0
+	DefaultVolatility = 0.06
+	glickoTau         = 0.5
+	glickoScale       = 173.7178
+	glickoEpsilon     = 0.000001
+)
+
+type Rating struct {
+	PlayerID   uuid.UUID
+	Rating     float64
+	RD         float64 // отклонение рейтинга: чем больше, тем меньше уверенность
+	Volatility float64
+	Games      int
+	UpdatedAt  time.Time // zero — игрок ещё не играл рейтинговых партий
+}
+
+type RatingChange struct {
+	GameID     uuid.UUID
+	PlayerID   uuid.UUID
+	Rating     float64
+	RD         float64
+	Volatility float64
+	Delta      float64
+	At         time.Time
+}
+
+// RateFunc получает текущие рейтинги игроков X и O и возвращает новые.
+type RateFunc func(x, o Rating) (Rating, Rating)
+
+func NewRating(playerID uuid.UUID) Rating {
+	return Rating{PlayerID: playerID, Rating: DefaultRating, RD: DefaultRD, Volatility: DefaultVolatility}
+}
+
+// Decayed увеличивает отклонение за каждый период без игр, но не выше DefaultRD.
+// Период может быть дробным, нулевой period отключает рост.
+func (r Rating) Decayed(now time.Time, period time.Duration) Rating {
+	if period <= 0 || r.UpdatedAt.IsZero() || !now.After(r.UpdatedAt) {
+		return r
+	}
+	periods := float64(now.Sub(r.UpdatedAt)) / float64(period)
+	phi := r.RD / glickoScale
+	phi = math.Sqrt(phi*phi + r.Volatility*r.Volatility*periods)
+	r.RD = min(phi*glickoScale, DefaultRD)
+	return r
+}
+
+// RateGame пересчитывает рейтинги по результату одной партии. scoreX — очки X:
+// 1 победа, 0.5 ничья, 0 поражение.
+func RateGame(x, o Rating, scoreX float64, now time.Time, period time.Duration) (Rating, Rating) {
+	x, o = x.Decayed(now, period), o.Decayed(now, period)
+	newX := glicko2(x, glickoResult{opponent: o, score: scoreX})
+	newO := glicko2(o, glickoResult{opponent: x, score: 1 - scoreX})
+	newX.UpdatedAt, newO.UpdatedAt = now, now
+	return newX, newO
+}
+
+// glickoResult — партия за период рейтинга: соперник и набранные очки.
+type glickoResult struct {
+	opponent Rating
+	score    float64
+}
+
+// glicko2 пересчитывает рейтинг по результатам одного периода (шаги 3–8 статьи).
+func glicko2(player Rating, results ...glickoResult) Rating {
+	mu := (player.Rating - DefaultRating) / glickoScale
+	phi := player.RD / glickoScale
+
+	var vInv, sum float64
+	for _, r := range results {
+		muJ := (r.opponent.Rating - DefaultRating) / glickoScale
+		phiJ := r.opponent.RD / glickoScale
+		g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		vInv += g * g * e * (1 - e)
+		sum += g * (r.score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma := volatility(phi, player.Volatility, v, delta)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * sum
+
+	player.Rating = mu*glickoScale + DefaultRating
+	player.RD = phi * glickoScale
+	player.Volatility = sigma
+	player.Games += len(results)
+	return player
+}
+
+// volatility решает уравнение для новой волатильности методом Иллинойса.
+func volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(glickoTau*glickoTau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glickoTau) < 0 {
+			k++
+		}
+		B = a - k*glickoTau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glickoEpsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func NewRatingChange(gameID uuid.UUID, before, after Rating) RatingChange {
+	return RatingChange{
+		GameID:     gameID,
+		PlayerID:   after.PlayerID,
+		Rating:     after.Rating,
+		RD:         after.RD,
+		Volatility: after.Volatility,
+		Delta:      after.Rating - before.Rating,
+		At:         after.UpdatedAt,
+	}
+}
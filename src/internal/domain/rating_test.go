@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func approx(t *testing.T, name string, got, want, eps float64) {
+	t.Helper()
+	if math.Abs(got-want) > eps {
+		t.Errorf("%s = %.5f, want %.5f", name, got, want)
+	}
+}
+
+func TestGlicko2(t *testing.T) {
+	rating := func(r, rd float64) Rating {
+		return Rating{Rating: r, RD: rd, Volatility: DefaultVolatility}
+	}
+	tests := []struct {
+		name       string
+		player     Rating
+		results    []glickoResult
+		rating, rd float64
+		volatility float64
+	}{
+		{
+			// Пример из статьи Glickman "Example of the Glicko-2 system".
+			name:   "glickman example",
+			player: rating(1500, 200),
+			results: []glickoResult{
+				{opponent: rating(1400, 30), score: 1},
+				{opponent: rating(1550, 100), score: 0},
+				{opponent: rating(1700, 300), score: 0},
+			},
+			rating: 1464.06, rd: 151.52, volatility: 0.05999,
+		},
+		{
+			name:    "newcomers win",
+			player:  NewRating(uuid.New()),
+			results: []glickoResult{{opponent: NewRating(uuid.New()), score: 1}},
+			rating:  1662.31, rd: 290.32, volatility: 0.06,
+		},
+		{
+			name:    "newcomers draw",
+			player:  NewRating(uuid.New()),
+			results: []glickoResult{{opponent: NewRating(uuid.New()), score: 0.5}},
+			rating:  1500, rd: 290.32, volatility: 0.06,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := glicko2(tt.player, tt.results...)
+			approx(t, "rating", got.Rating, tt.rating, 0.01)
+			approx(t, "rd", got.RD, tt.rd, 0.01)
+			approx(t, "volatility", got.Volatility, tt.volatility, 0.00001)
+			if got.Games != tt.player.Games+len(tt.results) {
+				t.Errorf("games = %d, want %d", got.Games, tt.player.Games+len(tt.results))
+			}
+		})
+	}
+}
+
+func TestRateGame(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	x, o := NewRating(uuid.New()), NewRating(uuid.New())
+
+	newX, newO := RateGame(x, o, 1, now, 24*time.Hour)
+	approx(t, "winner rating", newX.Rating, 1662.31, 0.01)
+	approx(t, "loser rating", newO.Rating, 1337.69, 0.01)
+	if newX.PlayerID != x.PlayerID || newO.PlayerID != o.PlayerID {
+		t.Error("players swapped")
+	}
+	if !newX.UpdatedAt.Equal(now) || !newO.UpdatedAt.Equal(now) {
+		t.Errorf("updated at = %v, %v, want %v", newX.UpdatedAt, newO.UpdatedAt, now)
+	}
+}
+
+func TestDecayed(t *testing.T) {
+	played := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	rating := func(rd float64, updatedAt time.Time) Rating {
+		return Rating{Rating: 1700, RD: rd, Volatility: DefaultVolatility, UpdatedAt: updatedAt}
+	}
+	tests := []struct {
+		name   string
+		rating Rating
+		now    time.Time
+		period time.Duration
+		rd     float64
+	}{
+		{"one period", rating(50, played), played.Add(day), day, 51.07},
+		{"half period", rating(50, played), played.Add(day / 2), day, 50.54},
+		{"capped at default", rating(340, played), played.Add(1000 * day), day, DefaultRD},
+		{"same moment", rating(50, played), played, day, 50},
+		{"clock behind", rating(50, played), played.Add(-day), day, 50},
+		{"decay disabled", rating(50, played), played.Add(100 * day), 0, 50},
+		{"never rated", rating(DefaultRD, time.Time{}), played, day, DefaultRD},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rating.Decayed(tt.now, tt.period)
+			approx(t, "rd", got.RD, tt.rd, 0.01)
+			if got.Rating != tt.rating.Rating || got.Volatility != tt.rating.Volatility || !got.UpdatedAt.Equal(tt.rating.UpdatedAt) {
+				t.Errorf("decay changed more than rd: %+v", got)
+			}
+		})
+	}
+}
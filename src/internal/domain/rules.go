@@ -0,0 +1,107 @@
+package domain
+
+var Directions = [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+func CheckGameOver(board Board, winLength int) (bool, Cell) {
+	full := true
+	for i := range board {
+		for j := range board[i] {
+			cell := board[i][j]
+			if cell == Empty {
+				full = false
+				continue
+			}
+			for _, d := range Directions {
+				if board.lineLength(i, j, d, cell) >= winLength {
+					return true, cell
+				}
+			}
+		}
+	}
+
+	return full, Empty
+}
+
+// WinningLine возвращает клетки первой найденной выигрышной линии или nil.
+func WinningLine(board Board, winLength int) [][2]int {
+	for i := range board {
+		for j := range board[i] {
+			cell := board[i][j]
+			if cell == Empty {
+				continue
+			}
+			for _, d := range Directions {
+				length := board.lineLength(i, j, d, cell)
+				if length < winLength {
+					continue
+				}
+				line := make([][2]int, length)
+				for k := range line {
+					line[k] = [2]int{i + k*d[0], j + k*d[1]}
+				}
+				return line
+			}
+		}
+	}
+	return nil
+}
+
+func (b Board) lineLength(row, col int, d [2]int, cell Cell) int {
+	length := 0
+	for b.Contains(row, col) && b[row][col] == cell {
+		length++
+		row += d[0]
+		col += d[1]
+	}
+	return length
+}
+
+func (b Board) Contains(row, col int) bool {
+	return row >= 0 && row < b.Height() && col >= 0 && col < b.Width()
+}
+
+func (b Board) MovesCount() int {
+	count := 0
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] != Empty {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (b Board) EmptyCells() [][2]int {
+	var cells [][2]int
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] == Empty {
+				cells = append(cells, [2]int{i, j})
+			}
+		}
+	}
+	return cells
+}
+
+func Opponent(cell Cell) Cell {
+	if cell == X {
+		return O
+	}
+	return X
+}
+
+func IsWinningMove(board Board, row, col, winLength int) bool {
+	cell := board[row][col]
+	if cell == Empty {
+		return false
+	}
+	for _, d := range Directions {
+		back := board.lineLength(row, col, [2]int{-d[0], -d[1]}, cell)
+		forward := board.lineLength(row, col, d, cell)
+		if back+forward-1 >= winLength {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"errors"
+	"t03/internal/domain"
+)
+
+const (
+	winScore        = 1_000_000
+	fullSearchCells = 9
+)
+
+var errNoMoves = errors.New("no moves left")
+
+// Каждое окно длины winLength, занятое только одним игроком, приносит
+// ему очки, растущие с количеством его фишек в окне.
+func evaluate(board domain.Board, winLength int, ai domain.Cell) int {
+	score := 0
+	for i := range board {
+		for j := range board[i] {
+			for _, d := range domain.Directions {
+				if !board.Contains(i+d[0]*(winLength-1), j+d[1]*(winLength-1)) {
+					continue
+				}
+				mine, theirs := 0, 0
+				for k := 0; k < winLength; k++ {
+					switch board[i+d[0]*k][j+d[1]*k] {
+					case ai:
+						mine++
+					case domain.Empty:
+					default:
+						theirs++
+					}
+				}
+				switch {
+				case theirs == 0 && mine > 0:
+					score += windowWeight(mine)
+				case mine == 0 && theirs > 0:
+					score -= windowWeight(theirs)
+				}
+			}
+		}
+	}
+	return score
+}
+
+func windowWeight(stones int) int {
+	weight := 1
+	for range stones {
+		weight *= 10
+	}
+	return weight
+}
+
+// Рассматриваем только клетки рядом с уже занятыми: на больших досках
+// дальние ходы почти никогда не бывают лучшими.
+func candidateMoves(board domain.Board) [][2]int {
+	if board.Width()*board.Height() <= fullSearchCells {
+		return board.EmptyCells()
+	}
+
+	var moves [][2]int
+	occupied := false
+	for i := range board {
+		for j := range board[i] {
+			if board[i][j] != domain.Empty {
+				occupied = true
+				continue
+			}
+			if hasNeighbour(board, i, j) {
+				moves = append(moves, [2]int{i, j})
+			}
+		}
+	}
+	if !occupied {
+		moves = append(moves, [2]int{board.Height() / 2, board.Width() / 2})
+	}
+	return moves
+}
+
+func hasNeighbour(board domain.Board, row, col int) bool {
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			if (di != 0 || dj != 0) && board.Contains(row+di, col+dj) && board[row+di][col+dj] != domain.Empty {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func movesLeft(board domain.Board) int {
+	return board.Width()*board.Height() - board.MovesCount()
+}
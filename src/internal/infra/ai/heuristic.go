@@ -0,0 +1,33 @@
+package ai
+
+import (
+	"math/rand/v2"
+	"t03/internal/domain"
+	"time"
+)
+
+const blunderRate = 0.3
+
+// Heuristic смотрит на один ход вперёд и время от времени зевает.
+type Heuristic struct {
+	random Random
+}
+
+func NewHeuristic() domain.AIEngine {
+	return &Heuristic{}
+}
+
+func (h *Heuristic) Name() string {
+	return "heuristic"
+}
+
+func (h *Heuristic) BestMove(board domain.Board, winLength int, ai domain.Cell, budget time.Duration) (domain.AIMove, error) {
+	if rand.Float64() < blunderRate {
+		return h.random.BestMove(board, winLength, ai, budget)
+	}
+	move, score := searchMove(board, winLength, ai, 0, time.Now().Add(budget))
+	if move[0] == -1 {
+		return domain.AIMove{}, errNoMoves
+	}
+	return domain.AIMove{Row: move[0], Col: move[1], Evaluation: max(-1, min(1, float64(score)/winScore))}, nil
+}
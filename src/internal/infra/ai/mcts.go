@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"math"
+	"math/rand/v2"
+	"t03/internal/domain"
+	"time"
+)
+
+const explorationWeight = math.Sqrt2
+
+type MCTS struct{}
+
+func NewMCTS() domain.AIEngine {
+	return &MCTS{}
+}
+
+func (m *MCTS) Name() string {
+	return "mcts"
+}
+
+type mctsNode struct {
+	move     [2]int
+	player   domain.Cell
+	parent   *mctsNode
+	children []*mctsNode
+	untried  [][2]int
+	terminal bool
+	visits   int
+	wins     float64
+}
+
+func (m *MCTS) BestMove(board domain.Board, winLength int, ai domain.Cell, budget time.Duration) (domain.AIMove, error) {
+	root := &mctsNode{player: domain.Opponent(ai), untried: candidateMoves(board)}
+	if len(root.untried) == 0 {
+		return domain.AIMove{}, errNoMoves
+	}
+
+	deadline := time.Now().Add(budget)
+	for root.visits == 0 || time.Now().Before(deadline) {
+		state := board.Clone()
+		node := root
+
+		// Выбор: спускаемся по полностью раскрытым узлам по UCT.
+		for len(node.untried) == 0 && len(node.children) > 0 {
+			node = node.selectChild()
+			state[node.move[0]][node.move[1]] = node.player
+		}
+
+		// Расширение: добавляем один ещё не опробованный ход.
+		if len(node.untried) > 0 && !node.terminal {
+			i := rand.IntN(len(node.untried))
+			move := node.untried[i]
+			node.untried[i] = node.untried[len(node.untried)-1]
+			node.untried = node.untried[:len(node.untried)-1]
+
+			player := domain.Opponent(node.player)
+			state[move[0]][move[1]] = player
+			child := &mctsNode{move: move, player: player, parent: node}
+			child.terminal = domain.IsWinningMove(state, move[0], move[1], winLength) || movesLeft(state) == 0
+			if !child.terminal {
+				child.untried = candidateMoves(state)
+			}
+			node.children = append(node.children, child)
+			node = child
+		}
+
+		winner := playout(state, winLength, node)
+
+		// Обратное распространение: узел хранит очки игрока, сделавшего его ход.
+		for ; node != nil; node = node.parent {
+			node.visits++
+			switch winner {
+			case node.player:
+				node.wins++
+			case domain.Empty:
+				node.wins += 0.5
+			}
+		}
+	}
+
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	return domain.AIMove{
+		Row:        best.move[0],
+		Col:        best.move[1],
+		Evaluation: 2*best.wins/float64(best.visits) - 1,
+	}, nil
+}
+
+func (n *mctsNode) selectChild() *mctsNode {
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, child := range n.children {
+		score := child.wins/float64(child.visits) +
+			explorationWeight*math.Sqrt(math.Log(float64(n.visits))/float64(child.visits))
+		if score > bestScore {
+			bestScore = score
+			best = child
+		}
+	}
+	return best
+}
+
+func playout(state domain.Board, winLength int, node *mctsNode) domain.Cell {
+	if node.parent != nil && domain.IsWinningMove(state, node.move[0], node.move[1], winLength) {
+		return node.player
+	}
+
+	player := node.player
+	for {
+		moves := candidateMoves(state)
+		if len(moves) == 0 {
+			return domain.Empty
+		}
+		player = domain.Opponent(player)
+		move := moves[rand.IntN(len(moves))]
+		state[move[0]][move[1]] = player
+		if domain.IsWinningMove(state, move[0], move[1], winLength) {
+			return player
+		}
+	}
+}
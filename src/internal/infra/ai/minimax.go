@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"math"
+	"t03/internal/domain"
+	"time"
+)
+
+const (
+	shallowDepth    = 2
+	deepDepth       = 4
+	deepSearchCells = 16
+)
+
+type Minimax struct {
+	name     string
+	maxDepth int
+}
+
+func NewMinimax() domain.AIEngine {
+	return &Minimax{name: "minimax", maxDepth: math.MaxInt}
+}
+
+func NewShallowMinimax() domain.AIEngine {
+	return &Minimax{name: "minimax-shallow", maxDepth: shallowDepth}
+}
+
+func (m *Minimax) Name() string {
+	return m.name
+}
+
+func (m *Minimax) BestMove(board domain.Board, winLength int, ai domain.Cell, budget time.Duration) (domain.AIMove, error) {
+	move, score := searchMove(board, winLength, ai, min(m.maxDepth, searchDepth(board)), time.Now().Add(budget))
+	if move[0] == -1 {
+		return domain.AIMove{}, errNoMoves
+	}
+	return domain.AIMove{Row: move[0], Col: move[1], Evaluation: max(-1, min(1, float64(score)/winScore))}, nil
+}
+
+// На маленьких досках перебираем всё дерево, на больших — ограничиваем глубину
+// и оцениваем позицию эвристикой.
+func searchDepth(board domain.Board) int {
+	if movesLeft(board) <= fullSearchCells {
+		return math.MaxInt
+	}
+	if board.Width()*board.Height() <= deepSearchCells {
+		return deepDepth
+	}
+	return shallowDepth
+}
+
+func searchMove(board domain.Board, winLength int, ai domain.Cell, maxDepth int, deadline time.Time) ([2]int, int) {
+	bestScore := math.MinInt
+	bestMove := [2]int{-1, -1}
+
+	for _, move := range candidateMoves(board) {
+		board[move[0]][move[1]] = ai
+		score := minimax(board, winLength, 0, maxDepth, math.MinInt, math.MaxInt, false, ai, deadline)
+		board[move[0]][move[1]] = domain.Empty
+
+		if score > bestScore {
+			bestScore = score
+			bestMove = move
+		}
+	}
+	return bestMove, bestScore
+}
+
+func minimax(board domain.Board, winLength, depth, maxDepth, alpha, beta int, isMaximizing bool, ai domain.Cell, deadline time.Time) int {
+	isOver, winner := domain.CheckGameOver(board, winLength)
+	if isOver {
+		if winner == ai {
+			return winScore - depth // победа ИИ
+		} else if winner != domain.Empty {
+			return depth - winScore // победа игрока
+		}
+		return 0 // ничья
+	}
+	if depth >= maxDepth || time.Now().After(deadline) {
+		return evaluate(board, winLength, ai)
+	}
+
+	player := domain.Opponent(ai)
+	if isMaximizing {
+		player = ai
+	}
+
+	best := math.MaxInt
+	if isMaximizing {
+		best = math.MinInt
+	}
+	for _, move := range candidateMoves(board) {
+		board[move[0]][move[1]] = player
+		score := minimax(board, winLength, depth+1, maxDepth, alpha, beta, !isMaximizing, ai, deadline)
+		board[move[0]][move[1]] = domain.Empty
+
+		if isMaximizing {
+			best = max(best, score)
+			alpha = max(alpha, best)
+		} else {
+			best = min(best, score)
+			beta = min(beta, best)
+		}
+		if beta <= alpha {
+			break
+		}
+	}
+	return best
+}
@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"math/rand/v2"
+	"t03/internal/domain"
+	"time"
+)
+
+type Random struct{}
+
+func NewRandom() domain.AIEngine {
+	return &Random{}
+}
+
+func (r *Random) Name() string {
+	return "random"
+}
+
+func (r *Random) BestMove(board domain.Board, _ int, _ domain.Cell, _ time.Duration) (domain.AIMove, error) {
+	cells := board.EmptyCells()
+	if len(cells) == 0 {
+		return domain.AIMove{}, errNoMoves
+	}
+	move := cells[rand.IntN(len(cells))]
+	return domain.AIMove{Row: move[0], Col: move[1]}, nil
+}
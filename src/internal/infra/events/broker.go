@@ -0,0 +1,117 @@
+package events
+
+import (
+	"sync"
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const subscriberBuffer = 16
+
+// В ленту лобби попадают только события, меняющие список открытых игр.
+var lobbyEvents = map[domain.EventType]bool{
+	domain.EventNewOpenGame:  true,
+	domain.EventPlayerJoined: true,
+	domain.EventAbandoned:    true,
+}
+
+// Личная лента игрока: события, которые он должен получить, не открывая партию.
+var playerEvents = map[domain.EventType]bool{
+	domain.EventPlayerJoined: true,
+	domain.EventMatchFound:   true,
+}
+
+type subscribers map[chan domain.GameEvent]struct{}
+
+type Broker struct {
+	mu      sync.RWMutex
+	games   map[uuid.UUID]subscribers
+	players map[uuid.UUID]subscribers
+	lobby   subscribers
+}
+
+func NewBroker() domain.GameEvents {
+	return &Broker{
+		games:   make(map[uuid.UUID]subscribers),
+		players: make(map[uuid.UUID]subscribers),
+		lobby:   make(subscribers),
+	}
+}
+
+func (b *Broker) Publish(event domain.GameEvent) {
+	event.Game = event.Game.Clone()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	send(b.games[event.Game.GameId], event)
+	if lobbyEvents[event.Type] {
+		send(b.lobby, event)
+	}
+	if playerEvents[event.Type] {
+		send(b.players[event.Game.Player_X], event)
+		send(b.players[event.Game.Player_O], event)
+	}
+}
+
+// Медленный подписчик пропускает событие, а не тормозит игру.
+func send(subs subscribers, event domain.GameEvent) {
+	for ch := range subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *Broker) Subscribe(gameID uuid.UUID) (<-chan domain.GameEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.games[gameID] == nil {
+		b.games[gameID] = make(subscribers)
+	}
+	return b.subscribe(b.games[gameID], func() {
+		if len(b.games[gameID]) == 0 {
+			delete(b.games, gameID)
+		}
+	})
+}
+
+func (b *Broker) SubscribePlayer(playerID uuid.UUID) (<-chan domain.GameEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.players[playerID] == nil {
+		b.players[playerID] = make(subscribers)
+	}
+	return b.subscribe(b.players[playerID], func() {
+		if len(b.players[playerID]) == 0 {
+			delete(b.players, playerID)
+		}
+	})
+}
+
+func (b *Broker) SubscribeLobby() (<-chan domain.GameEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.subscribe(b.lobby, func() {})
+}
+
+func (b *Broker) subscribe(subs subscribers, cleanup func()) (<-chan domain.GameEvent, func()) {
+	ch := make(chan domain.GameEvent, subscriberBuffer)
+	subs[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(subs, ch)
+			cleanup()
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
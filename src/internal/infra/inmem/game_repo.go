@@ -0,0 +1,191 @@
+package inmem
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"t03/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GameRepositoryImpl struct {
+	mu     sync.RWMutex
+	games  map[uuid.UUID]*domain.Game
+	users  map[string]*domain.User
+	tokens map[string]*domain.RefreshToken
+	// Рейтинги и их история, новые изменения в конце.
+	ratings       map[uuid.UUID]domain.Rating
+	ratingHistory map[uuid.UUID][]domain.RatingChange
+}
+
+func NewGameRepository() domain.GameRepository {
+	return &GameRepositoryImpl{
+		games:         make(map[uuid.UUID]*domain.Game),
+		users:         make(map[string]*domain.User),
+		tokens:        make(map[string]*domain.RefreshToken),
+		ratings:       make(map[uuid.UUID]domain.Rating),
+		ratingHistory: make(map[uuid.UUID][]domain.RatingChange),
+	}
+}
+
+func (repo *GameRepositoryImpl) SaveGame(game *domain.Game) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.saveGame(game)
+}
+
+func (repo *GameRepositoryImpl) saveGame(game *domain.Game) error {
+	stored, ok := repo.games[game.GameId]
+	version := 0
+	if ok {
+		version = stored.Version
+	}
+	if game.Version != version {
+		return domain.ErrConflict
+	}
+	game.Version++
+	repo.games[game.GameId] = game.Clone()
+	return nil
+}
+
+func (repo *GameRepositoryImpl) GetGame(id string) (*domain.Game, error) {
+	gameID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	game, ok := repo.games[gameID]
+	if !ok {
+		return nil, domain.NewError(domain.ErrNotFound, "game not found")
+	}
+	return game.Clone(), nil
+}
+
+// Повторяет memory.ListGamesQuery.
+func (repo *GameRepositoryImpl) ListGames(query domain.GamesQuery) (*domain.GamesPage, error) {
+	filter := query.Filter
+	if filter == (domain.GamesFilter{}) {
+		filter = domain.GamesFilter{Mine: true, Open: true, AI: true}
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	logins := make(map[uuid.UUID]string, len(repo.users))
+	for _, user := range repo.users {
+		logins[user.ID] = user.Login
+	}
+
+	var games []domain.GameSummary
+	for _, game := range repo.games {
+		mine := game.Player_X == query.PlayerID || game.Player_O == query.PlayerID
+		active := game.State == domain.StatusWaiting || game.State == domain.StatusTurn
+		match := filter.Mine && mine && active && game.Mode != domain.PVE ||
+			filter.AI && mine && active && game.Mode == domain.PVE ||
+			filter.Finished && mine && !active ||
+			filter.Open && !mine && game.State == domain.StatusWaiting && game.Mode != domain.PVE
+		if !match || query.After != nil && !before(game, query.After) {
+			continue
+		}
+		games = append(games, domain.GameSummary{
+			GameID:     game.GameId,
+			Mode:       game.Mode,
+			State:      game.State,
+			Player_X:   game.Player_X,
+			Player_O:   game.Player_O,
+			LoginX:     logins[game.Player_X],
+			LoginO:     logins[game.Player_O],
+			CurrentPID: game.CurrentPID,
+			WinnerPID:  game.WinnerPID,
+			CreatedAt:  game.CreatedAt,
+			UpdatedAt:  game.UpdatedAt,
+		})
+	}
+	slices.SortFunc(games, func(a, b domain.GameSummary) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.GameID.String(), a.GameID.String())
+	})
+
+	page := &domain.GamesPage{Games: games[:min(len(games), query.Limit)]}
+	if len(games) > query.Limit {
+		last := page.Games[len(page.Games)-1]
+		page.Next = &domain.GamesCursor{CreatedAt: last.CreatedAt, GameID: last.GameID}
+	}
+	return page, nil
+}
+
+func (repo *GameRepositoryImpl) GetExpiredGames(now time.Time) (uuid.UUIDs, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var ids uuid.UUIDs
+	for id, game := range repo.games {
+		if game.State == domain.StatusTurn && !game.TurnDeadline.IsZero() && !game.TurnDeadline.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (repo *GameRepositoryImpl) GetStaleGames(state domain.GameState, updatedBefore time.Time) (uuid.UUIDs, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var ids uuid.UUIDs
+	for id, game := range repo.games {
+		if game.State == state && game.UpdatedAt.Before(updatedBefore) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func before(game *domain.Game, cursor *domain.GamesCursor) bool {
+	if !game.CreatedAt.Equal(cursor.CreatedAt) {
+		return game.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return game.GameId.String() < cursor.GameID.String()
+}
+
+// Повторяет statsQuery: в счёт идут все партии игрока, включая ожидающие соперника,
+// кроме брошенных до его подключения.
+func (repo *GameRepositoryImpl) GetPlayerStats(playerID uuid.UUID) (*domain.Stats, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var s domain.Stats
+	for _, game := range repo.games {
+		if game.Player_X != playerID && game.Player_O != playerID {
+			continue
+		}
+		forfeited := game.Mode == domain.PVE || game.WinnerPID != uuid.Nil
+		if game.State == domain.StatusAbandoned && !forfeited {
+			continue
+		}
+		s.TotalGames++
+		switch {
+		case game.WinnerPID == playerID:
+			s.Wins++
+		case game.State == domain.StatusWin || game.State == domain.StatusResigned || game.State == domain.StatusTimeout:
+			s.Losses++
+		case game.State == domain.StatusAbandoned:
+			s.Losses++
+			s.Abandoned++
+		case game.State == domain.StatusDraw:
+			s.Draws++
+		}
+	}
+	if s.TotalGames > 0 {
+		s.WinRatePct = math.Round(1000*float64(s.Wins)/float64(s.TotalGames)) / 10
+	}
+	return &s, nil
+}
@@ -0,0 +1,24 @@
+package inmem
+
+import (
+	"testing"
+
+	"t03/internal/domain"
+	"t03/internal/infra/repotest"
+)
+
+func newTestRepo(t *testing.T) domain.GameRepository {
+	return NewGameRepository()
+}
+
+func TestOptimisticLock(t *testing.T) {
+	repotest.TestOptimisticLock(t, newTestRepo)
+}
+
+func TestListGames(t *testing.T) {
+	repotest.TestListGames(t, newTestRepo)
+}
+
+func TestLeaderboard(t *testing.T) {
+	repotest.TestLeaderboard(t, newTestRepo)
+}
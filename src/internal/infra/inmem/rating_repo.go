@@ -0,0 +1,136 @@
+package inmem
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (repo *GameRepositoryImpl) SaveRatedGame(game *domain.Game, rate domain.RateFunc) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if err := repo.saveGame(game); err != nil {
+		return err
+	}
+	x, o := repo.rating(game.Player_X), repo.rating(game.Player_O)
+	newX, newO := rate(x, o)
+	for _, r := range [][2]domain.Rating{{x, newX}, {o, newO}} {
+		repo.ratings[r[1].PlayerID] = r[1]
+		repo.ratingHistory[r[1].PlayerID] = append(repo.ratingHistory[r[1].PlayerID], domain.NewRatingChange(game.GameId, r[0], r[1]))
+	}
+	return nil
+}
+
+func (repo *GameRepositoryImpl) rating(playerID uuid.UUID) domain.Rating {
+	if rating, ok := repo.ratings[playerID]; ok {
+		return rating
+	}
+	return domain.NewRating(playerID)
+}
+
+func (repo *GameRepositoryImpl) GetRating(playerID uuid.UUID) (*domain.Rating, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	rating := repo.rating(playerID)
+	return &rating, nil
+}
+
+func (repo *GameRepositoryImpl) GetRatingHistory(playerID uuid.UUID, limit int) ([]domain.RatingChange, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	history := repo.ratingHistory[playerID]
+	changes := make([]domain.RatingChange, 0, min(len(history), limit))
+	for i := len(history) - 1; i >= 0 && len(changes) < limit; i-- {
+		changes = append(changes, history[i])
+	}
+	return changes, nil
+}
+
+// GetLeaderboard повторяет leaderboardQuery.
+func (repo *GameRepositoryImpl) GetLeaderboard(query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	totals := make(map[uuid.UUID]*domain.LeaderboardEntry)
+	for _, game := range repo.games {
+		if !finishedInWindow(game, query) {
+			continue
+		}
+		for _, playerID := range []uuid.UUID{game.Player_X, game.Player_O} {
+			if playerID == uuid.Nil {
+				continue
+			}
+			entry, ok := totals[playerID]
+			if !ok {
+				entry = &domain.LeaderboardEntry{PlayerID: playerID}
+				totals[playerID] = entry
+			}
+			entry.Games++
+			switch {
+			case game.WinnerPID == playerID:
+				entry.Wins++
+			case game.State == domain.StatusDraw:
+				entry.Draws++
+			default:
+				entry.Losses++
+			}
+		}
+	}
+
+	logins := make(map[uuid.UUID]string, len(repo.users))
+	for _, user := range repo.users {
+		logins[user.ID] = user.Login
+	}
+	var entries []domain.LeaderboardEntry
+	for playerID, entry := range totals {
+		login, ok := logins[playerID]
+		if !ok || entry.Games < query.MinGames {
+			continue
+		}
+		entry.Login = login
+		entry.WinRatePct = math.Round(1000*float64(entry.Wins)/float64(entry.Games)) / 10
+		entry.Rating = repo.rating(playerID)
+		entries = append(entries, *entry)
+	}
+
+	// Ключи сортировки по убыванию, как ORDER BY в leaderboardQuery.
+	key := func(e domain.LeaderboardEntry) [2]float64 {
+		switch query.Sort {
+		case domain.LeaderboardByWins:
+			return [2]float64{float64(e.Wins), e.WinRatePct}
+		case domain.LeaderboardByWinRate:
+			return [2]float64{e.WinRatePct, float64(e.Games)}
+		}
+		return [2]float64{e.Rating.Rating, float64(e.Games)}
+	}
+	slices.SortFunc(entries, func(a, b domain.LeaderboardEntry) int {
+		ka, kb := key(a), key(b)
+		if c := cmp.Or(cmp.Compare(kb[0], ka[0]), cmp.Compare(kb[1], ka[1])); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PlayerID.String(), b.PlayerID.String())
+	})
+	return entries[:min(len(entries), query.Limit)], nil
+}
+
+func finishedInWindow(game *domain.Game, query domain.LeaderboardQuery) bool {
+	switch game.State {
+	case domain.StatusWaiting, domain.StatusTurn:
+		return false
+	case domain.StatusAbandoned:
+		if game.Mode != domain.PVE && game.WinnerPID == uuid.Nil {
+			return false
+		}
+	}
+	if len(query.Modes) > 0 && !slices.Contains(query.Modes, game.Mode) {
+		return false
+	}
+	return query.Since.IsZero() || !game.UpdatedAt.Before(query.Since)
+}
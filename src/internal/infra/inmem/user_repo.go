@@ -0,0 +1,89 @@
+package inmem
+
+import (
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (repo *GameRepositoryImpl) SaveUser(user *domain.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.users[user.Login]; ok {
+		return domain.NewError(domain.ErrConflict, "user already exists")
+	}
+	saved := *user
+	repo.users[user.Login] = &saved
+	return nil
+}
+
+func (repo *GameRepositoryImpl) GetUser(login string) (*domain.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	user, ok := repo.users[login]
+	if !ok {
+		return nil, domain.NewError(domain.ErrNotFound, "user not found")
+	}
+	found := *user
+	return &found, nil
+}
+
+func (repo *GameRepositoryImpl) UpdateUserPassword(userID uuid.UUID, password string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, user := range repo.users {
+		if user.ID == userID {
+			user.Password = password
+			return nil
+		}
+	}
+	return domain.NewError(domain.ErrNotFound, "user not found")
+}
+
+func (repo *GameRepositoryImpl) SaveRefreshToken(token *domain.RefreshToken) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	saved := *token
+	repo.tokens[token.TokenHash] = &saved
+	return nil
+}
+
+func (repo *GameRepositoryImpl) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	token, ok := repo.tokens[tokenHash]
+	if !ok {
+		return nil, domain.NewError(domain.ErrNotFound, "refresh token not found")
+	}
+	found := *token
+	return &found, nil
+}
+
+func (repo *GameRepositoryImpl) RevokeRefreshToken(tokenHash string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, ok := repo.tokens[tokenHash]
+	if !ok || token.Revoked {
+		return false, nil
+	}
+	token.Revoked = true
+	return true, nil
+}
+
+func (repo *GameRepositoryImpl) RevokeUserRefreshTokens(userID uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, token := range repo.tokens {
+		if token.UserID == userID {
+			token.Revoked = true
+		}
+	}
+	return nil
+}
@@ -2,34 +2,62 @@ package memory
 
 import (
 	"errors"
-	"github.com/google/uuid"
+	"strconv"
 	"strings"
 	"t03/internal/domain"
+	"time"
 )
 
-func toEntity(game *domain.Game) *GameEntity {
+func ToEntity(game *domain.Game) *GameEntity {
 	var boardBuilder strings.Builder
 	for i := range game.Board {
 		for j := range game.Board[i] {
 			boardBuilder.WriteByte('0' + byte(game.Board[i][j]))
 		}
 	}
+	if game.Ultimate != nil {
+		boardBuilder.WriteString(encodeUltimate(game.Ultimate))
+	}
 
 	return &GameEntity{
-		GameId:     game.GameId,
-		Board:      boardBuilder.String(),
-		Mode:       int(game.Mode),
-		Player_X:   game.Player_X,
-		Player_O:   game.Player_O,
-		State:      int(game.State),
-		CurrentPID: game.CurrentPID,
-		WinnerPID:  game.WinnerPID,
+		GameId:        game.GameId,
+		Board:         boardBuilder.String(),
+		Width:         game.Board.Width(),
+		Height:        game.Board.Height(),
+		WinLength:     game.WinLength,
+		Difficulty:    int(game.Difficulty),
+		AIEngine:      game.Engine,
+		Mode:          int(game.Mode),
+		Player_X:      game.Player_X,
+		Player_O:      game.Player_O,
+		State:         int(game.State),
+		CurrentPID:    game.CurrentPID,
+		WinnerPID:     game.WinnerPID,
+		DrawOffer:     game.DrawOfferedBy,
+		PerMoveMs:     game.TimeControl.PerMove.Milliseconds(),
+		ClockMs:       game.TimeControl.Clock.Milliseconds(),
+		IncrementMs:   game.TimeControl.Increment.Milliseconds(),
+		ClockXMs:      game.ClockX.Milliseconds(),
+		ClockOMs:      game.ClockO.Milliseconds(),
+		TurnStartedAt: toNullTime(game.TurnStartedAt),
+		TurnDeadline:  toNullTime(game.TurnDeadline),
+		Version:       game.Version,
+		CreatedAt:     game.CreatedAt,
+		UpdatedAt:     game.UpdatedAt,
 	}
 }
 
-func toDomain(entity *GameEntity) (*domain.Game, error) {
+func ToDomain(entity *GameEntity) (*domain.Game, error) {
+	width, height, winLength := entity.Width, entity.Height, entity.WinLength
+	if width == 0 || height == 0 {
+		width, height = domain.DefaultBoardSize, domain.DefaultBoardSize
+	}
+	if winLength == 0 {
+		winLength = domain.DefaultBoardSize
+	}
+
 	k := 0
-	board := domain.Board{}
+	board := domain.NewBoard(width, height)
 
 	for i := range board {
 		for j := range board[i] {
@@ -45,21 +73,154 @@ func toDomain(entity *GameEntity) (*domain.Game, error) {
 		}
 	}
 
-	return &domain.Game{
-		GameId:     entity.GameId,
-		Board:      board,
-		Mode:       domain.Gametype(entity.Mode),
-		Player_X:   entity.Player_X,
-		Player_O:   entity.Player_O,
-		State:      domain.GameState(entity.State),
-		CurrentPID: entity.CurrentPID,
-		WinnerPID:  entity.WinnerPID,
-	}, nil
+	game := &domain.Game{
+		GameId:        entity.GameId,
+		Board:         board,
+		WinLength:     winLength,
+		Difficulty:    domain.Difficulty(entity.Difficulty),
+		Engine:        entity.AIEngine,
+		Mode:          domain.Gametype(entity.Mode),
+		Player_X:      entity.Player_X,
+		Player_O:      entity.Player_O,
+		State:         domain.GameState(entity.State),
+		CurrentPID:    entity.CurrentPID,
+		WinnerPID:     entity.WinnerPID,
+		DrawOfferedBy: entity.DrawOffer,
+		TimeControl: domain.TimeControl{
+			PerMove:   time.Duration(entity.PerMoveMs) * time.Millisecond,
+			Clock:     time.Duration(entity.ClockMs) * time.Millisecond,
+			Increment: time.Duration(entity.IncrementMs) * time.Millisecond,
+		},
+		ClockX:        time.Duration(entity.ClockXMs) * time.Millisecond,
+		ClockO:        time.Duration(entity.ClockOMs) * time.Millisecond,
+		TurnStartedAt: fromNullTime(entity.TurnStartedAt),
+		TurnDeadline:  fromNullTime(entity.TurnDeadline),
+		Version:       entity.Version,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+	if game.Mode == domain.ULTIMATE {
+		ultimate, err := decodeUltimate(entity.Board[k:])
+		if err != nil {
+			return nil, err
+		}
+		game.Ultimate = ultimate
+	}
+	return game, nil
+}
+
+// Для ultimate после клеток поля хранится "/<активная доска>/<итоги малых досок>",
+// итог доски: '-' — идёт игра, '=' — ничья, иначе символ победителя.
+func encodeUltimate(ultimate *domain.UltimateBoard) string {
+	var builder strings.Builder
+	builder.WriteByte('/')
+	builder.WriteString(strconv.Itoa(ultimate.ActiveSubBoard))
+	builder.WriteByte('/')
+	for _, sub := range ultimate.SubBoards {
+		switch sub.State {
+		case domain.StatusWin:
+			builder.WriteByte('0' + byte(sub.Winner))
+		case domain.StatusDraw:
+			builder.WriteByte('=')
+		default:
+			builder.WriteByte('-')
+		}
+	}
+	return builder.String()
+}
+
+func decodeUltimate(data string) (*domain.UltimateBoard, error) {
+	parts := strings.Split(data, "/")
+	if len(parts) != 3 || parts[0] != "" || len(parts[2]) != domain.SubBoardsCount {
+		return nil, errors.New("invalid ultimate board data")
+	}
+	active, err := strconv.Atoi(parts[1])
+	if err != nil || active < domain.AnySubBoard || active >= domain.SubBoardsCount {
+		return nil, errors.New("invalid active sub-board")
+	}
+
+	ultimate := domain.NewUltimateBoard()
+	ultimate.ActiveSubBoard = active
+	for i := 0; i < domain.SubBoardsCount; i++ {
+		switch c := parts[2][i]; c {
+		case '-':
+		case '=':
+			ultimate.SubBoards[i] = domain.SubBoard{State: domain.StatusDraw}
+		case '1', '2':
+			ultimate.SubBoards[i] = domain.SubBoard{State: domain.StatusWin, Winner: domain.Cell(c - '0')}
+		default:
+			return nil, errors.New("invalid sub-board state")
+		}
+	}
+	return ultimate, nil
+}
+
+func ToMoveEntities(game *domain.Game) []MoveEntity {
+	entities := make([]MoveEntity, 0, len(game.Moves))
+	for _, move := range game.Moves {
+		entities = append(entities, MoveEntity{
+			GameId:   game.GameId,
+			Number:   move.Number,
+			PlayerID: move.PlayerID,
+			Row:      move.Row,
+			Col:      move.Col,
+			Symbol:   int(move.Symbol),
+			MadeAt:   move.MadeAt,
+		})
+	}
+	return entities
 }
 
-func ToDomainGamesList(games uuid.UUIDs) *domain.GamesList {
-	return &domain.GamesList{
-		Games: games,
+func ToDomainMoves(entities []MoveEntity) []domain.Move {
+	moves := make([]domain.Move, 0, len(entities))
+	for _, entity := range entities {
+		moves = append(moves, domain.Move{
+			Number:   entity.Number,
+			PlayerID: entity.PlayerID,
+			Row:      entity.Row,
+			Col:      entity.Col,
+			Symbol:   domain.Cell(entity.Symbol),
+			MadeAt:   entity.MadeAt,
+		})
 	}
+	return moves
+}
 
+func toNullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func fromNullTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// Репозиторий запрашивает на одну партию больше лимита, чтобы узнать, есть ли следующая страница.
+func ToDomainGamesPage(entities []GameSummaryEntity, limit int) *domain.GamesPage {
+	page := &domain.GamesPage{Games: make([]domain.GameSummary, 0, min(len(entities), limit))}
+	for _, entity := range entities[:min(len(entities), limit)] {
+		page.Games = append(page.Games, domain.GameSummary{
+			GameID:     entity.GameId,
+			Mode:       domain.Gametype(entity.Mode),
+			State:      domain.GameState(entity.State),
+			Player_X:   entity.Player_X,
+			Player_O:   entity.Player_O,
+			LoginX:     entity.LoginX,
+			LoginO:     entity.LoginO,
+			CurrentPID: entity.CurrentPID,
+			WinnerPID:  entity.WinnerPID,
+			CreatedAt:  entity.CreatedAt,
+			UpdatedAt:  entity.UpdatedAt,
+		})
+	}
+	if len(entities) > limit {
+		last := page.Games[len(page.Games)-1]
+		page.Next = &domain.GamesCursor{CreatedAt: last.CreatedAt, GameID: last.GameID}
+	}
+	return page
 }
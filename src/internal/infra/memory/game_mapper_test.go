@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestUltimateRoundTrip(t *testing.T) {
+	mixed := domain.NewUltimateBoard()
+	mixed.ActiveSubBoard = 7
+	mixed.SubBoards[0] = domain.SubBoard{State: domain.StatusWin, Winner: domain.X}
+	mixed.SubBoards[4] = domain.SubBoard{State: domain.StatusDraw}
+	mixed.SubBoards[8] = domain.SubBoard{State: domain.StatusWin, Winner: domain.O}
+
+	allFinished := domain.NewUltimateBoard()
+	for i := range allFinished.SubBoards {
+		allFinished.SubBoards[i] = domain.SubBoard{State: domain.StatusDraw}
+	}
+
+	tests := []struct {
+		name     string
+		ultimate *domain.UltimateBoard
+		suffix   string
+	}{
+		{"new game", domain.NewUltimateBoard(), "/-1/---------"},
+		{"finished sub-boards", mixed, "/7/1---=---2"},
+		{"all sub-boards drawn", allFinished, "/-1/========="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := &domain.Game{
+				GameId:    uuid.New(),
+				Mode:      domain.ULTIMATE,
+				Board:     domain.NewBoard(domain.UltimateBoardSize, domain.UltimateBoardSize),
+				WinLength: domain.SubBoardSize,
+				Ultimate:  tt.ultimate,
+			}
+			game.Board[0][0], game.Board[8][8] = domain.X, domain.O
+
+			entity := ToEntity(game)
+			if !strings.HasSuffix(entity.Board, tt.suffix) {
+				t.Errorf("board %q does not end with %q", entity.Board, tt.suffix)
+			}
+			got, err := ToDomain(entity)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Ultimate, game.Ultimate) || !reflect.DeepEqual(got.Board, game.Board) {
+				t.Errorf("got %+v, %v, want %+v, %v", got.Ultimate, got.Board, game.Ultimate, game.Board)
+			}
+		})
+	}
+}
+
+func TestDecodeUltimateRejectsCorruptData(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"/-1",
+		"-1/---------",
+		"/-1/--------",
+		"/-1/----------",
+		"/9/---------",
+		"/-2/---------",
+		"/x/---------",
+		"/0/---3-----",
+		"/0/---------/",
+	} {
+		if _, err := decodeUltimate(data); err == nil {
+			t.Errorf("decodeUltimate(%q) succeeded", data)
+		}
+	}
+}
+
+func TestClassicBoardHasNoUltimate(t *testing.T) {
+	game := &domain.Game{GameId: uuid.New(), Mode: domain.PVP, Board: domain.NewBoard(3, 3), WinLength: 3}
+	got, err := ToDomain(ToEntity(game))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Ultimate != nil {
+		t.Errorf("classic game decoded with ultimate board %+v", got.Ultimate)
+	}
+}
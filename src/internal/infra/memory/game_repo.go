@@ -2,7 +2,12 @@ package memory
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"strconv"
+	"strings"
 	"t03/internal/domain"
 	"time"
 )
@@ -15,32 +20,106 @@ func NewGameRepository(storage *Storage) domain.GameRepository {
 	return &GameRepositoryImpl{storage: storage}
 }
 
+// $14 — версия, с которой партия была прочитана; обновление проходит,
+// только если с тех пор её никто не сохранил.
 const saveGameQuery = `
-	INSERT INTO game_sessions (id, board_state, mode, player_x, player_o, state, turn, winner)
-	VALUES ($1, $2, $3, $4, $5, $6, $7,$8)
+	INSERT INTO game_sessions (id, board_state, width, height, win_length, difficulty, ai_engine, mode, player_x, player_o, state, turn, winner, version, created_at, updated_at, draw_offer, per_move_ms, clock_ms, increment_ms, clock_x_ms, clock_o_ms, turn_started_at, turn_deadline)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14 + 1, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 	ON CONFLICT (id) DO UPDATE
 	SET board_state = EXCLUDED.board_state,
+	    player_x = EXCLUDED.player_x,
 	    player_o = EXCLUDED.player_o,
 	    state     = EXCLUDED.state,
 	    turn      = EXCLUDED.turn,
-	    winner    = EXCLUDED.winner
+	    winner    = EXCLUDED.winner,
+	    version   = EXCLUDED.version,
+	    updated_at = EXCLUDED.updated_at,
+	    draw_offer = EXCLUDED.draw_offer,
+	    clock_x_ms = EXCLUDED.clock_x_ms,
+	    clock_o_ms = EXCLUDED.clock_o_ms,
+	    turn_started_at = EXCLUDED.turn_started_at,
+	    turn_deadline = EXCLUDED.turn_deadline
+	WHERE game_sessions.version = $14
 `
 
 const getGameQuery = `
-		SELECT id, board_state, mode, player_x, player_o, state, turn,  winner
+		SELECT id, board_state, width, height, win_length, difficulty, ai_engine, mode, player_x, player_o, state, turn, winner, version, created_at, updated_at, draw_offer,
+		       per_move_ms, clock_ms, increment_ms, clock_x_ms, clock_o_ms, turn_started_at, turn_deadline
 		FROM game_sessions
 		WHERE id = $1
 	`
-const getAvalableGamesQuery = `
+const saveMoveQuery = `
+	INSERT INTO game_moves (game_id, move_number, player_id, row_idx, col_idx, symbol, made_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (game_id, move_number) DO NOTHING
+`
+
+const getMovesQuery = `
+		SELECT game_id, move_number, player_id, row_idx, col_idx, symbol, made_at
+		FROM game_moves
+		WHERE game_id = $1
+		ORDER BY move_number
+	`
+const staleGamesQuery = `
     SELECT id
     FROM game_sessions
-    WHERE state IN (0,1)
-      AND (
-           player_x = $1
-        OR player_o = $1
-        OR (player_o = $2
-            AND player_x <> $1 AND mode<>1)
-      )`
+    WHERE state = $1 AND updated_at < $2
+    ORDER BY updated_at
+    LIMIT 100`
+
+const expiredGamesQuery = `
+    SELECT id
+    FROM game_sessions
+    WHERE state = 1 AND turn_deadline <= $1
+    ORDER BY turn_deadline
+    LIMIT 100`
+
+const listGamesQuery = `
+    SELECT g.id, g.mode, g.state, g.player_x, g.player_o,
+           COALESCE(ux.user_login, '') AS login_x, COALESCE(uo.user_login, '') AS login_o,
+           g.turn, g.winner, g.created_at, g.updated_at
+    FROM game_sessions g
+    LEFT JOIN users ux ON ux.id = g.player_x
+    LEFT JOIN users uo ON uo.id = g.player_o
+    WHERE %s
+    ORDER BY g.created_at DESC, g.id DESC
+    LIMIT %s`
+
+// ListGamesQuery собирает listGamesQuery под фильтр. placeholder — префикс
+// параметров драйвера: "$" для Postgres, "?" для SQLite.
+func ListGamesQuery(query domain.GamesQuery, placeholder string) (string, []any) {
+	filter := query.Filter
+	if filter == (domain.GamesFilter{}) {
+		filter = domain.GamesFilter{Mine: true, Open: true, AI: true}
+	}
+
+	// state 0,1 — ожидание и игра; mode 1 — партия против ИИ.
+	const mine = "(g.player_x = $1 OR g.player_o = $1)"
+	var conditions []string
+	if filter.Mine {
+		conditions = append(conditions, mine+" AND g.state IN (0,1) AND g.mode <> 1")
+	}
+	if filter.AI {
+		conditions = append(conditions, mine+" AND g.state IN (0,1) AND g.mode = 1")
+	}
+	if filter.Finished {
+		conditions = append(conditions, mine+" AND g.state NOT IN (0,1)")
+	}
+	if filter.Open {
+		conditions = append(conditions, "g.state = 0 AND g.mode <> 1 AND g.player_x <> $1 AND g.player_o <> $1")
+	}
+
+	args := []any{query.PlayerID}
+	where := "((" + strings.Join(conditions, ") OR (") + "))"
+	if query.After != nil {
+		args = append(args, query.After.CreatedAt, query.After.GameID)
+		where += " AND (g.created_at, g.id) < ($2, $3)"
+	}
+	args = append(args, query.Limit+1)
+
+	sql := fmt.Sprintf(listGamesQuery, where, "$"+strconv.Itoa(len(args)))
+	return strings.ReplaceAll(sql, "$", placeholder), args
+}
 
 const statsQuery = `
 WITH my_games AS (
@@ -51,7 +130,9 @@ WITH my_games AS (
             WHEN player_o = $1 THEN 'O'
         END AS role,
         winner,
-        state 
+        state,
+        -- партию бросили после начала: у ИИ-партии соперник есть всегда
+        mode = 1 OR winner <> '00000000-0000-0000-0000-000000000000' AS forfeited
     FROM
         game_sessions
     WHERE
@@ -69,7 +150,7 @@ SELECT
     SUM(
         CASE
             WHEN winner <> $1
-            AND state = 3 THEN 1
+            AND (state IN (3, 4, 5) OR state = 6 AND forfeited) THEN 1
             ELSE 0
         END
     ) AS losses,
@@ -79,6 +160,13 @@ SELECT
             ELSE 0
         END
     ) AS draws,
+    SUM(
+        CASE
+            WHEN winner <> $1
+            AND state = 6 AND forfeited THEN 1
+            ELSE 0
+        END
+    ) AS abandoned,
     ROUND(
         100.0 * SUM(
             CASE
@@ -89,15 +177,19 @@ SELECT
         1
     ) AS win_rate_pct
 FROM
-    my_games;
+    my_games
+-- партия, брошенная до подключения соперника, не сыграна
+WHERE
+    state <> 6
+    OR forfeited;
 `
 
 func (repo *GameRepositoryImpl) GetPlayerStats(playerID uuid.UUID) (*domain.Stats, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := repo.storage.queryContext()
 	defer cancel()
 	var s domain.Stats
 	if err := repo.storage.pool.QueryRow(ctx, statsQuery, playerID).Scan(
-		&s.TotalGames, &s.Wins, &s.Losses, &s.Draws, &s.WinRatePct,
+		&s.TotalGames, &s.Wins, &s.Losses, &s.Draws, &s.Abandoned, &s.WinRatePct,
 	); err != nil {
 		return nil, err
 	}
@@ -105,53 +197,110 @@ func (repo *GameRepositoryImpl) GetPlayerStats(playerID uuid.UUID) (*domain.Stat
 }
 
 func (repo *GameRepositoryImpl) SaveGame(game *domain.Game) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := repo.storage.txContext()
 	defer cancel()
 
-	entity := toEntity(game)
+	err := pgx.BeginFunc(ctx, repo.storage.pool, func(tx pgx.Tx) error {
+		return saveGame(ctx, tx, game)
+	})
+	if err != nil {
+		return err
+	}
+	game.Version++
+	return nil
+}
+
+func saveGame(ctx context.Context, tx pgx.Tx, game *domain.Game) error {
+	entity := ToEntity(game)
 
-	_, err := repo.storage.pool.Exec(ctx, saveGameQuery, entity.GameId, entity.Board, entity.Mode, entity.Player_X, entity.Player_O, entity.State, entity.CurrentPID, entity.WinnerPID)
+	batch := &pgx.Batch{}
+	batch.Queue(saveGameQuery, entity.GameId, entity.Board, entity.Width, entity.Height, entity.WinLength, entity.Difficulty, entity.AIEngine, entity.Mode, entity.Player_X, entity.Player_O, entity.State, entity.CurrentPID, entity.WinnerPID, entity.Version, entity.CreatedAt, entity.UpdatedAt, entity.DrawOffer,
+		entity.PerMoveMs, entity.ClockMs, entity.IncrementMs, entity.ClockXMs, entity.ClockOMs, entity.TurnStartedAt, entity.TurnDeadline)
+	for _, move := range ToMoveEntities(game) {
+		batch.Queue(saveMoveQuery, move.GameId, move.Number, move.PlayerID, move.Row, move.Col, move.Symbol, move.MadeAt)
+	}
 
-	return err
+	results := tx.SendBatch(ctx, batch)
+	tag, err := results.Exec()
+	if err != nil {
+		results.Close()
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		results.Close()
+		return domain.ErrConflict
+	}
+	return results.Close()
 }
 
 func (repo *GameRepositoryImpl) GetGame(id string) (*domain.Game, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := repo.storage.queryContext()
 	defer cancel()
 
 	var entity GameEntity
 
-	err := repo.storage.pool.QueryRow(ctx, getGameQuery, id).Scan(&entity.GameId, &entity.Board, &entity.Mode, &entity.Player_X, &entity.Player_O, &entity.State, &entity.CurrentPID, &entity.WinnerPID)
+	err := repo.storage.pool.QueryRow(ctx, getGameQuery, id).Scan(&entity.GameId, &entity.Board, &entity.Width, &entity.Height, &entity.WinLength, &entity.Difficulty, &entity.AIEngine, &entity.Mode, &entity.Player_X, &entity.Player_O, &entity.State, &entity.CurrentPID, &entity.WinnerPID, &entity.Version, &entity.CreatedAt, &entity.UpdatedAt, &entity.DrawOffer,
+		&entity.PerMoveMs, &entity.ClockMs, &entity.IncrementMs, &entity.ClockXMs, &entity.ClockOMs, &entity.TurnStartedAt, &entity.TurnDeadline)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "game not found")
+	}
+	if err != nil {
+		return nil, err
+	}
 
+	game, err := ToDomain(&entity)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.storage.pool.Query(ctx, getMovesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	moves, err := pgx.CollectRows(rows, pgx.RowToStructByName[MoveEntity])
 	if err != nil {
 		return nil, err
 	}
+	game.Moves = ToDomainMoves(moves)
 
-	return toDomain(&entity)
+	return game, nil
 }
-func (repo *GameRepositoryImpl) GetAvailableGames(pid string) (*domain.GamesList, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+
+func (repo *GameRepositoryImpl) ListGames(query domain.GamesQuery) (*domain.GamesPage, error) {
+	ctx, cancel := repo.storage.queryContext()
 	defer cancel()
-	zero := uuid.Nil
 
-	rows, err := repo.storage.pool.Query(ctx, getAvalableGamesQuery, pid, zero)
+	listQuery, args := ListGamesQuery(query, "$")
+	rows, err := repo.storage.pool.Query(ctx, listQuery, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var ids uuid.UUIDs
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		ids = append(ids, id)
+	games, err := pgx.CollectRows(rows, pgx.RowToStructByName[GameSummaryEntity])
+	if err != nil {
+		return nil, err
 	}
+	return ToDomainGamesPage(games, query.Limit), nil
+}
+
+func (repo *GameRepositoryImpl) GetExpiredGames(now time.Time) (uuid.UUIDs, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
 
-	if err := rows.Err(); err != nil {
+	rows, err := repo.storage.pool.Query(ctx, expiredGamesQuery, now)
+	if err != nil {
 		return nil, err
 	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+func (repo *GameRepositoryImpl) GetStaleGames(state domain.GameState, updatedBefore time.Time) (uuid.UUIDs, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
 
-	return ToDomainGamesList(ids), nil
+	rows, err := repo.storage.pool.Query(ctx, staleGamesQuery, state, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
 }
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"t03/internal/domain"
+	"t03/internal/infra/repotest"
+)
+
+// Тесты пишут в настоящую базу, поэтому запускаются только с TEST_DATABASE_DSN.
+// Перед каждым подтестом база очищается: выборки вроде открытых партий видят все данные.
+func newTestRepo(t *testing.T) domain.GameRepository {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	pool, err := Open(Config{DSN: dsn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Exec(context.Background(), "TRUNCATE game_sessions, users CASCADE"); err != nil {
+		t.Fatal(err)
+	}
+	storage := &Storage{pool: pool, timeout: 5 * time.Second, txTimeout: 5 * time.Second}
+	return NewGameRepository(storage)
+}
+
+func TestOptimisticLock(t *testing.T) {
+	repotest.TestOptimisticLock(t, newTestRepo)
+}
+
+func TestListGames(t *testing.T) {
+	repotest.TestListGames(t, newTestRepo)
+}
+
+func TestLeaderboard(t *testing.T) {
+	repotest.TestLeaderboard(t, newTestRepo)
+}
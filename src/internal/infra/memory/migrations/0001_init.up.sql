@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id          UUID     PRIMARY KEY,
+    board_state TEXT     NOT NULL,
+    mode        SMALLINT NOT NULL,
+    player_x    UUID     NOT NULL,
+    player_o    UUID     NOT NULL,
+    state       SMALLINT NOT NULL,
+    turn        UUID     NOT NULL,
+    winner      UUID     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS game_sessions_player_x_idx ON game_sessions (player_x);
+CREATE INDEX IF NOT EXISTS game_sessions_player_o_idx ON game_sessions (player_o);
+
+CREATE TABLE IF NOT EXISTS users (
+    id            UUID PRIMARY KEY,
+    user_login    TEXT NOT NULL UNIQUE,
+    user_password TEXT NOT NULL
+);
@@ -0,0 +1,6 @@
+ALTER TABLE game_sessions
+    DROP COLUMN IF EXISTS ai_engine,
+    DROP COLUMN IF EXISTS difficulty,
+    DROP COLUMN IF EXISTS win_length,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width;
@@ -0,0 +1,6 @@
+ALTER TABLE game_sessions
+    ADD COLUMN IF NOT EXISTS width      SMALLINT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS height     SMALLINT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS win_length SMALLINT NOT NULL DEFAULT 3,
+    ADD COLUMN IF NOT EXISTS difficulty SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS ai_engine  TEXT     NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS game_moves (
+    game_id     UUID        NOT NULL REFERENCES game_sessions (id) ON DELETE CASCADE,
+    move_number INTEGER     NOT NULL,
+    player_id   UUID        NOT NULL,
+    row_idx     SMALLINT    NOT NULL,
+    col_idx     SMALLINT    NOT NULL,
+    symbol      SMALLINT    NOT NULL,
+    made_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (game_id, move_number)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS game_sessions_created_at_idx;
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE game_sessions DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,15 @@
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE game_sessions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Для старых партий время восстанавливается по истории ходов.
+UPDATE game_sessions g
+SET created_at = m.first_move,
+    updated_at = m.last_move
+FROM (
+    SELECT game_id, MIN(made_at) AS first_move, MAX(made_at) AS last_move
+    FROM game_moves
+    GROUP BY game_id
+) m
+WHERE m.game_id = g.id;
+
+CREATE INDEX IF NOT EXISTS game_sessions_created_at_idx ON game_sessions (created_at DESC, id DESC);
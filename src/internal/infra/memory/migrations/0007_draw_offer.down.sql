@@ -0,0 +1,4 @@
+-- Сдавшиеся партии в старой схеме не выразить, считаем их обычной победой соперника.
+UPDATE game_sessions SET state = 3 WHERE state = 4;
+ALTER TABLE game_sessions
+    DROP COLUMN IF EXISTS draw_offer;
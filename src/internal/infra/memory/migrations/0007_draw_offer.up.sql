@@ -0,0 +1,2 @@
+ALTER TABLE game_sessions
+    ADD COLUMN IF NOT EXISTS draw_offer UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
@@ -0,0 +1,10 @@
+UPDATE game_sessions SET state = 3 WHERE state = 5;
+DROP INDEX IF EXISTS game_sessions_turn_deadline_idx;
+ALTER TABLE game_sessions
+    DROP COLUMN IF EXISTS per_move_ms,
+    DROP COLUMN IF EXISTS clock_ms,
+    DROP COLUMN IF EXISTS increment_ms,
+    DROP COLUMN IF EXISTS clock_x_ms,
+    DROP COLUMN IF EXISTS clock_o_ms,
+    DROP COLUMN IF EXISTS turn_started_at,
+    DROP COLUMN IF EXISTS turn_deadline;
@@ -0,0 +1,10 @@
+ALTER TABLE game_sessions
+    ADD COLUMN IF NOT EXISTS per_move_ms     BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS clock_ms        BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS increment_ms    BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS clock_x_ms      BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS clock_o_ms      BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS turn_started_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS turn_deadline   TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS game_sessions_turn_deadline_idx ON game_sessions (turn_deadline) WHERE state = 1;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS game_sessions_state_updated_at_idx ON game_sessions (state, updated_at);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS player_ratings (
+    player_id  UUID             PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    rating     DOUBLE PRECISION NOT NULL,
+    rd         DOUBLE PRECISION NOT NULL,
+    volatility DOUBLE PRECISION NOT NULL,
+    games      INTEGER          NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS rating_history (
+    game_id    UUID             NOT NULL REFERENCES game_sessions (id) ON DELETE CASCADE,
+    player_id  UUID             NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating     DOUBLE PRECISION NOT NULL,
+    rd         DOUBLE PRECISION NOT NULL,
+    volatility DOUBLE PRECISION NOT NULL,
+    delta      DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ      NOT NULL,
+    PRIMARY KEY (game_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS rating_history_player_id_idx ON rating_history (player_id, created_at DESC);
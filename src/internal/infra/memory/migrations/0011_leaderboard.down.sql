@@ -0,0 +1 @@
+DROP INDEX IF EXISTS game_sessions_finished_idx;
@@ -1,23 +1,108 @@
 package memory
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type GameEntity struct {
-	GameId        uuid.UUID `db:"id"`
-	Board         string    `db:"board_state"`
-	Mode          int       `db:"mode"`
-	Player_X      uuid.UUID `db:"player_x"`
-	Player_O      uuid.UUID `db:"player_o"`
-	State         int       `db:"state"`
-	CurrentPID    uuid.UUID `db:"turn"`
-	CurrentSimbol int       `db:"current_simbol"`
-	WinnerPID     uuid.UUID `db:"winner"`
+	GameId        uuid.UUID  `db:"id"`
+	Board         string     `db:"board_state"`
+	Width         int        `db:"width"`
+	Height        int        `db:"height"`
+	WinLength     int        `db:"win_length"`
+	Difficulty    int        `db:"difficulty"`
+	AIEngine      string     `db:"ai_engine"`
+	Mode          int        `db:"mode"`
+	Player_X      uuid.UUID  `db:"player_x"`
+	Player_O      uuid.UUID  `db:"player_o"`
+	State         int        `db:"state"`
+	CurrentPID    uuid.UUID  `db:"turn"`
+	CurrentSimbol int        `db:"current_simbol"`
+	WinnerPID     uuid.UUID  `db:"winner"`
+	DrawOffer     uuid.UUID  `db:"draw_offer"`
+	PerMoveMs     int64      `db:"per_move_ms"`
+	ClockMs       int64      `db:"clock_ms"`
+	IncrementMs   int64      `db:"increment_ms"`
+	ClockXMs      int64      `db:"clock_x_ms"`
+	ClockOMs      int64      `db:"clock_o_ms"`
+	TurnStartedAt *time.Time `db:"turn_started_at"`
+	TurnDeadline  *time.Time `db:"turn_deadline"`
+	Version       int        `db:"version"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+type GameSummaryEntity struct {
+	GameId     uuid.UUID `db:"id"`
+	Mode       int       `db:"mode"`
+	State      int       `db:"state"`
+	Player_X   uuid.UUID `db:"player_x"`
+	Player_O   uuid.UUID `db:"player_o"`
+	LoginX     string    `db:"login_x"`
+	LoginO     string    `db:"login_o"`
+	CurrentPID uuid.UUID `db:"turn"`
+	WinnerPID  uuid.UUID `db:"winner"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type MoveEntity struct {
+	GameId   uuid.UUID `db:"game_id"`
+	Number   int       `db:"move_number"`
+	PlayerID uuid.UUID `db:"player_id"`
+	Row      int       `db:"row_idx"`
+	Col      int       `db:"col_idx"`
+	Symbol   int       `db:"symbol"`
+	MadeAt   time.Time `db:"made_at"`
 }
 
 type UserEntity struct {
 	ID       uuid.UUID `db:"id"`
 	Login    string    `db:"user_login"`
-	Password string    `db:"user_status"`
+	Password string    `db:"user_password"`
+}
+
+type RefreshTokenEntity struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type RatingEntity struct {
+	PlayerID   uuid.UUID  `db:"player_id"`
+	Rating     float64    `db:"rating"`
+	RD         float64    `db:"rd"`
+	Volatility float64    `db:"volatility"`
+	Games      int        `db:"games"`
+	UpdatedAt  *time.Time `db:"updated_at"`
+}
+
+type RatingChangeEntity struct {
+	GameID     uuid.UUID `db:"game_id"`
+	PlayerID   uuid.UUID `db:"player_id"`
+	Rating     float64   `db:"rating"`
+	RD         float64   `db:"rd"`
+	Volatility float64   `db:"volatility"`
+	Delta      float64   `db:"delta"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type LeaderboardEntryEntity struct {
+	PlayerID   uuid.UUID  `db:"player_id"`
+	Login      string     `db:"login"`
+	Games      int        `db:"games"`
+	Wins       int        `db:"wins"`
+	Losses     int        `db:"losses"`
+	Draws      int        `db:"draws"`
+	WinRatePct float64    `db:"win_rate_pct"`
+	Rating     float64    `db:"rating"`
+	RD         float64    `db:"rd"`
+	Volatility float64    `db:"volatility"`
+	RatedGames int        `db:"rated_games"`
+	RatedAt    *time.Time `db:"rated_at"`
 }
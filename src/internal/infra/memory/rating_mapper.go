@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"t03/internal/domain"
+)
+
+func RatingToEntity(rating domain.Rating) RatingEntity {
+	return RatingEntity{
+		PlayerID:   rating.PlayerID,
+		Rating:     rating.Rating,
+		RD:         rating.RD,
+		Volatility: rating.Volatility,
+		Games:      rating.Games,
+		UpdatedAt:  toNullTime(rating.UpdatedAt),
+	}
+}
+
+func RatingToDomain(entity RatingEntity) domain.Rating {
+	return domain.Rating{
+		PlayerID:   entity.PlayerID,
+		Rating:     entity.Rating,
+		RD:         entity.RD,
+		Volatility: entity.Volatility,
+		Games:      entity.Games,
+		UpdatedAt:  fromNullTime(entity.UpdatedAt),
+	}
+}
+
+func RatingChangeToEntity(change domain.RatingChange) RatingChangeEntity {
+	return RatingChangeEntity{
+		GameID:     change.GameID,
+		PlayerID:   change.PlayerID,
+		Rating:     change.Rating,
+		RD:         change.RD,
+		Volatility: change.Volatility,
+		Delta:      change.Delta,
+		CreatedAt:  change.At,
+	}
+}
+
+func RatingChangesToDomain(entities []RatingChangeEntity) []domain.RatingChange {
+	changes := make([]domain.RatingChange, 0, len(entities))
+	for _, e := range entities {
+		changes = append(changes, domain.RatingChange{
+			GameID:     e.GameID,
+			PlayerID:   e.PlayerID,
+			Rating:     e.Rating,
+			RD:         e.RD,
+			Volatility: e.Volatility,
+			Delta:      e.Delta,
+			At:         e.CreatedAt,
+		})
+	}
+	return changes
+}
+
+func LeaderboardToDomain(entities []LeaderboardEntryEntity) []domain.LeaderboardEntry {
+	entries := make([]domain.LeaderboardEntry, 0, len(entities))
+	for _, e := range entities {
+		entries = append(entries, domain.LeaderboardEntry{
+			PlayerID:   e.PlayerID,
+			Login:      e.Login,
+			Games:      e.Games,
+			Wins:       e.Wins,
+			Losses:     e.Losses,
+			Draws:      e.Draws,
+			WinRatePct: e.WinRatePct,
+			Rating: RatingToDomain(RatingEntity{
+				PlayerID:   e.PlayerID,
+				Rating:     e.Rating,
+				RD:         e.RD,
+				Volatility: e.Volatility,
+				Games:      e.RatedGames,
+				UpdatedAt:  e.RatedAt,
+			}),
+		})
+	}
+	return entries
+}
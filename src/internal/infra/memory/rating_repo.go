@@ -0,0 +1,208 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"t03/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const ratingQuery = `
+    SELECT player_id, rating, rd, volatility, games, updated_at
+    FROM player_ratings
+    WHERE player_id = $1`
+
+const initRatingQuery = `
+    INSERT INTO player_ratings (player_id, rating, rd, volatility)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (player_id) DO NOTHING`
+
+const updateRatingQuery = `
+    UPDATE player_ratings
+    SET rating = $2, rd = $3, volatility = $4, games = $5, updated_at = $6
+    WHERE player_id = $1`
+
+const saveRatingChangeQuery = `
+    INSERT INTO rating_history (game_id, player_id, rating, rd, volatility, delta, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+const ratingHistoryQuery = `
+    SELECT game_id, player_id, rating, rd, volatility, delta, created_at
+    FROM rating_history
+    WHERE player_id = $1
+    ORDER BY created_at DESC
+    LIMIT $2`
+
+// Каждая партия даёт по строке на участника; таблица считается одним проходом
+// по завершённым партиям окна, а не statsQuery на каждого игрока.
+const leaderboardQuery = `
+WITH participants AS (
+    SELECT g.player_x AS player_id, g.winner, g.state FROM game_sessions g WHERE %[1]s
+    UNION ALL
+    SELECT g.player_o AS player_id, g.winner, g.state FROM game_sessions g WHERE %[1]s
+),
+totals AS (
+    SELECT
+        player_id,
+        COUNT(*) AS games,
+        SUM(CASE WHEN winner = player_id THEN 1 ELSE 0 END) AS wins,
+        SUM(CASE WHEN winner <> player_id AND state <> 2 THEN 1 ELSE 0 END) AS losses,
+        SUM(CASE WHEN state = 2 THEN 1 ELSE 0 END) AS draws
+    FROM participants
+    WHERE player_id <> '00000000-0000-0000-0000-000000000000'
+    GROUP BY player_id
+    HAVING COUNT(*) >= $1
+)
+SELECT t.player_id, u.user_login AS login, t.games, t.wins, t.losses, t.draws,
+       ROUND(100.0 * t.wins / t.games, 1) AS win_rate_pct,
+       COALESCE(r.rating, $2) AS rating, COALESCE(r.rd, $3) AS rd,
+       COALESCE(r.volatility, $4) AS volatility, COALESCE(r.games, 0) AS rated_games,
+       r.updated_at AS rated_at
+FROM totals t
+JOIN users u ON u.id = t.player_id
+LEFT JOIN player_ratings r ON r.player_id = t.player_id
+ORDER BY %[2]s, t.player_id
+LIMIT %[3]s`
+
+var leaderboardOrder = map[domain.LeaderboardSort]string{
+	domain.LeaderboardByRating:  "rating DESC, t.games DESC",
+	domain.LeaderboardByWins:    "t.wins DESC, win_rate_pct DESC",
+	domain.LeaderboardByWinRate: "win_rate_pct DESC, t.games DESC",
+}
+
+// LeaderboardQuery собирает leaderboardQuery под запрос, placeholder — как в ListGamesQuery.
+func LeaderboardQuery(query domain.LeaderboardQuery, placeholder string) (string, []any) {
+	args := []any{query.MinGames, domain.DefaultRating, domain.DefaultRD, domain.DefaultVolatility}
+
+	// Партия, брошенная до подключения соперника, не сыграна.
+	where := "g.state >= 2 AND (g.state <> 6 OR g.mode = 1 OR g.winner <> '00000000-0000-0000-0000-000000000000')"
+	if len(query.Modes) > 0 {
+		modes := make([]string, len(query.Modes))
+		for i, mode := range query.Modes {
+			modes[i] = strconv.Itoa(int(mode))
+		}
+		where += " AND g.mode IN (" + strings.Join(modes, ", ") + ")"
+	}
+	if !query.Since.IsZero() {
+		args = append(args, query.Since)
+		where += " AND g.updated_at >= $" + strconv.Itoa(len(args))
+	}
+	args = append(args, query.Limit)
+
+	sql := fmt.Sprintf(leaderboardQuery, where, leaderboardOrder[query.Sort], "$"+strconv.Itoa(len(args)))
+	return strings.ReplaceAll(sql, "$", placeholder), args
+}
+
+func (repo *GameRepositoryImpl) GetLeaderboard(query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	boardQuery, args := LeaderboardQuery(query, "$")
+	rows, err := repo.storage.pool.Query(ctx, boardQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := pgx.CollectRows(rows, pgx.RowToStructByName[LeaderboardEntryEntity])
+	if err != nil {
+		return nil, err
+	}
+	return LeaderboardToDomain(entities), nil
+}
+
+func (repo *GameRepositoryImpl) SaveRatedGame(game *domain.Game, rate domain.RateFunc) error {
+	ctx, cancel := repo.storage.txContext()
+	defer cancel()
+
+	err := pgx.BeginFunc(ctx, repo.storage.pool, func(tx pgx.Tx) error {
+		if err := saveGame(ctx, tx, game); err != nil {
+			return err
+		}
+
+		// Блокируем строки в одном порядке, чтобы параллельные партии одних
+		// и тех же игроков не взаимоблокировались.
+		first, second := game.Player_X, game.Player_O
+		if first.String() > second.String() {
+			first, second = second, first
+		}
+		ratings := make(map[uuid.UUID]domain.Rating, 2)
+		for _, id := range []uuid.UUID{first, second} {
+			rating, err := lockRating(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			ratings[id] = rating
+		}
+
+		x, o := ratings[game.Player_X], ratings[game.Player_O]
+		newX, newO := rate(x, o)
+		batch := &pgx.Batch{}
+		for _, r := range [][2]domain.Rating{{x, newX}, {o, newO}} {
+			entity := RatingToEntity(r[1])
+			change := RatingChangeToEntity(domain.NewRatingChange(game.GameId, r[0], r[1]))
+			batch.Queue(updateRatingQuery, entity.PlayerID, entity.Rating, entity.RD, entity.Volatility, entity.Games, entity.UpdatedAt)
+			batch.Queue(saveRatingChangeQuery, change.GameID, change.PlayerID, change.Rating, change.RD, change.Volatility, change.Delta, change.CreatedAt)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return err
+	}
+	game.Version++
+	return nil
+}
+
+func lockRating(ctx context.Context, tx pgx.Tx, playerID uuid.UUID) (domain.Rating, error) {
+	initial := domain.NewRating(playerID)
+	if _, err := tx.Exec(ctx, initRatingQuery, playerID, initial.Rating, initial.RD, initial.Volatility); err != nil {
+		return initial, err
+	}
+	rows, err := tx.Query(ctx, ratingQuery+" FOR UPDATE", playerID)
+	if err != nil {
+		return initial, err
+	}
+	entity, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[RatingEntity])
+	if err != nil {
+		return initial, err
+	}
+	return RatingToDomain(entity), nil
+}
+
+func (repo *GameRepositoryImpl) GetRating(playerID uuid.UUID) (*domain.Rating, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rows, err := repo.storage.pool.Query(ctx, ratingQuery, playerID)
+	if err != nil {
+		return nil, err
+	}
+	entity, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[RatingEntity])
+	if errors.Is(err, pgx.ErrNoRows) {
+		rating := domain.NewRating(playerID)
+		return &rating, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rating := RatingToDomain(entity)
+	return &rating, nil
+}
+
+func (repo *GameRepositoryImpl) GetRatingHistory(playerID uuid.UUID, limit int) ([]domain.RatingChange, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rows, err := repo.storage.pool.Query(ctx, ratingHistoryQuery, playerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := pgx.CollectRows(rows, pgx.RowToStructByName[RatingChangeEntity])
+	if err != nil {
+		return nil, err
+	}
+	return RatingChangesToDomain(entities), nil
+}
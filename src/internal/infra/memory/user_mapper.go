@@ -4,7 +4,7 @@ import (
 	"t03/internal/domain"
 )
 
-func userToEntity(user *domain.User) *UserEntity {
+func UserToEntity(user *domain.User) *UserEntity {
 	return &UserEntity{
 		ID:       user.ID,
 		Login:    user.Login,
@@ -12,10 +12,31 @@ func userToEntity(user *domain.User) *UserEntity {
 	}
 }
 
-func userToDomain(entity *UserEntity) *domain.User {
+func UserToDomain(entity *UserEntity) *domain.User {
 	return &domain.User{
 		ID:       entity.ID,
 		Login:    entity.Login,
 		Password: entity.Password,
 	}
 }
+
+func RefreshTokenToEntity(token *domain.RefreshToken) *RefreshTokenEntity {
+	return &RefreshTokenEntity{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+}
+
+func RefreshTokenToDomain(entity *RefreshTokenEntity) *domain.RefreshToken {
+	return &domain.RefreshToken{
+		ID:        entity.ID,
+		UserID:    entity.UserID,
+		TokenHash: entity.TokenHash,
+		ExpiresAt: entity.ExpiresAt,
+		CreatedAt: entity.CreatedAt,
+		Revoked:   entity.RevokedAt != nil,
+	}
+}
@@ -1,16 +1,17 @@
 package memory
 
 import (
-	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"t03/internal/domain"
-	"time"
 )
 
 func (repo *GameRepositoryImpl) SaveUser(user *domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := repo.storage.queryContext()
 	defer cancel()
 
-	entity := userToEntity(user)
+	entity := UserToEntity(user)
 
 	_, err := repo.storage.pool.Exec(ctx, `
 		INSERT INTO users (id,user_login, user_password)
@@ -21,7 +22,7 @@ func (repo *GameRepositoryImpl) SaveUser(user *domain.User) error {
 }
 
 func (repo *GameRepositoryImpl) GetUser(login string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	ctx, cancel := repo.storage.queryContext()
 	defer cancel()
 	var entity UserEntity
 	err := repo.storage.pool.QueryRow(ctx, `
@@ -30,9 +31,88 @@ func (repo *GameRepositoryImpl) GetUser(login string) (*domain.User, error) {
 		WHERE user_login = $1
 	`, login).Scan(&entity.ID, &entity.Login, &entity.Password)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return UserToDomain(&entity), nil
+}
+
+func (repo *GameRepositoryImpl) UpdateUserPassword(userID uuid.UUID, password string) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	_, err := repo.storage.pool.Exec(ctx, `
+		UPDATE users
+		SET user_password = $2
+		WHERE id = $1
+	`, userID, password)
+
+	return err
+}
+
+func (repo *GameRepositoryImpl) SaveRefreshToken(token *domain.RefreshToken) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	entity := RefreshTokenToEntity(token)
+
+	_, err := repo.storage.pool.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, entity.ID, entity.UserID, entity.TokenHash, entity.ExpiresAt, entity.CreatedAt)
+
+	return err
+}
+
+func (repo *GameRepositoryImpl) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+	var entity RefreshTokenEntity
+	err := repo.storage.pool.QueryRow(ctx, `
+		SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`, tokenHash).Scan(&entity.ID, &entity.UserID, &entity.TokenHash, &entity.ExpiresAt, &entity.CreatedAt, &entity.RevokedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "refresh token not found")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return userToDomain(&entity), nil
+	return RefreshTokenToDomain(&entity), nil
+}
+
+func (repo *GameRepositoryImpl) RevokeRefreshToken(tokenHash string) (bool, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	tag, err := repo.storage.pool.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (repo *GameRepositoryImpl) RevokeUserRefreshTokens(userID uuid.UUID) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	_, err := repo.storage.pool.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+
+	return err
 }
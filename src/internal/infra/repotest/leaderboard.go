@@ -0,0 +1,185 @@
+package repotest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"t03/internal/domain"
+)
+
+type boardGame struct {
+	mode        domain.Gametype
+	state       domain.GameState
+	x, o        string // логины игроков, "" — ИИ или нет игрока
+	winner      string
+	finishedAgo time.Duration
+}
+
+const day = 24 * time.Hour
+
+// Итоги за всё время: a — 5 партий, 3 победы, 1 поражение, 1 ничья; b — 3, 2, 1, 0;
+// c — 2, 0, 2, 0; d — 3, 1, 1, 1.
+var boardFixture = []boardGame{
+	{domain.PVP, domain.StatusWin, "a", "b", "a", 40 * day},
+	{domain.PVP, domain.StatusResigned, "c", "a", "a", 10 * day},
+	{domain.PVP, domain.StatusDraw, "a", "d", "", time.Hour},
+	{domain.PVP, domain.StatusTimeout, "b", "c", "b", time.Hour},
+	{domain.PVP, domain.StatusAbandoned, "d", "b", "b", 2 * time.Hour},
+	// Брошена до подключения соперника — не сыграна.
+	{domain.PVP, domain.StatusAbandoned, "c", "d", "", 3 * time.Hour},
+	{domain.ULTIMATE, domain.StatusWin, "d", "a", "d", time.Hour},
+	{domain.PVE, domain.StatusWin, "a", "", "a", time.Hour},
+	{domain.PVP, domain.StatusTurn, "a", "b", "", 0},
+}
+
+var boardRatings = map[string]float64{"a": 1600, "b": 1700, "c": 1400, "d": 1500}
+
+// TestLeaderboard проверяет подсчёт, сортировку, порог числа партий и окно
+// по времени завершения: все реализации должны строить одинаковую таблицу.
+func TestLeaderboard(t *testing.T, newRepo NewRepo) {
+	tests := []struct {
+		name  string
+		query domain.LeaderboardQuery
+		since time.Duration // окно от текущего момента, 0 — всё время
+		// Ожидаемый порядок; игроки внутри группы равны по ключам сортировки
+		// и идут по возрастанию id.
+		want  [][]string
+		games map[string]int
+	}{
+		{"by rating", domain.LeaderboardQuery{Sort: domain.LeaderboardByRating}, 0, [][]string{{"b"}, {"a"}, {"d"}, {"c"}}, nil},
+		{"by wins", domain.LeaderboardQuery{Sort: domain.LeaderboardByWins}, 0, [][]string{{"a"}, {"b"}, {"d"}, {"c"}}, nil},
+		{"by win rate", domain.LeaderboardQuery{Sort: domain.LeaderboardByWinRate}, 0, [][]string{{"b"}, {"a"}, {"d"}, {"c"}}, nil},
+		{"min games", domain.LeaderboardQuery{MinGames: 3}, 0, [][]string{{"b"}, {"a"}, {"d"}}, nil},
+		{"limit", domain.LeaderboardQuery{Limit: 2}, 0, [][]string{{"b"}, {"a"}}, nil},
+		{"ultimate only", domain.LeaderboardQuery{Sort: domain.LeaderboardByWins, Modes: []domain.Gametype{domain.ULTIMATE}}, 0,
+			[][]string{{"d"}, {"a"}}, map[string]int{"a": 1, "d": 1}},
+		{"human and ai", domain.LeaderboardQuery{Sort: domain.LeaderboardByWinRate, Modes: []domain.Gametype{domain.PVP, domain.PVE}, MinGames: 3}, 0,
+			[][]string{{"a"}, {"b"}}, map[string]int{"a": 4, "b": 3}},
+		{"ties", domain.LeaderboardQuery{Sort: domain.LeaderboardByWins, Modes: []domain.Gametype{domain.PVP}}, 0,
+			[][]string{{"a", "b"}, {"c", "d"}}, nil},
+		{"window", domain.LeaderboardQuery{Sort: domain.LeaderboardByWins}, 20 * day,
+			[][]string{{"b"}, {"a"}, {"d"}, {"c"}}, map[string]int{"a": 4, "b": 2, "c": 2, "d": 3}},
+		{"window starts at finish time", domain.LeaderboardQuery{}, 10 * day, [][]string{{"b"}, {"a"}, {"d"}, {"c"}}, map[string]int{"a": 4, "c": 2}},
+		{"window starts after finish time", domain.LeaderboardQuery{}, 10*day - time.Millisecond, [][]string{{"b"}, {"a"}, {"d"}, {"c"}}, map[string]int{"a": 3, "c": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t)
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			users := saveBoardFixture(t, repo, now)
+
+			query := tt.query
+			if query.MinGames == 0 {
+				query.MinGames = 1
+			}
+			if query.Limit == 0 {
+				query.Limit = 10
+			}
+			if tt.since > 0 {
+				query.Since = now.Add(-tt.since)
+			}
+			entries, err := repo.GetLeaderboard(query)
+			if err != nil {
+				t.Fatalf("leaderboard: %v", err)
+			}
+
+			var want, got []string
+			for _, group := range tt.want {
+				group = slices.Clone(group)
+				slices.SortFunc(group, func(a, b string) int { return compareIDs(users[a], users[b]) })
+				want = append(want, group...)
+			}
+			for _, entry := range entries {
+				got = append(got, entry.Login)
+				if entry.PlayerID != users[entry.Login] {
+					t.Errorf("%s: player id %s, want %s", entry.Login, entry.PlayerID, users[entry.Login])
+				}
+				if games, ok := tt.games[entry.Login]; ok && entry.Games != games {
+					t.Errorf("%s: %d games, want %d", entry.Login, entry.Games, games)
+				}
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("leaderboard = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("totals", func(t *testing.T) {
+		repo := newRepo(t)
+		saveBoardFixture(t, repo, time.Now().UTC().Truncate(time.Millisecond))
+		entries, err := repo.GetLeaderboard(domain.LeaderboardQuery{MinGames: 1, Limit: 10})
+		if err != nil {
+			t.Fatalf("leaderboard: %v", err)
+		}
+
+		type totals struct {
+			games, wins, losses, draws int
+			winRate, rating            float64
+		}
+		want := map[string]totals{
+			"a": {5, 3, 1, 1, 60, 1600},
+			"b": {3, 2, 1, 0, 66.7, 1700},
+			"c": {2, 0, 2, 0, 0, 1400},
+			"d": {3, 1, 1, 1, 33.3, 1500},
+		}
+		for _, entry := range entries {
+			got := totals{entry.Games, entry.Wins, entry.Losses, entry.Draws, entry.WinRatePct, entry.Rating.Rating}
+			if got != want[entry.Login] {
+				t.Errorf("%s: %+v, want %+v", entry.Login, got, want[entry.Login])
+			}
+		}
+		if len(entries) != len(want) {
+			t.Errorf("got %d entries, want %d", len(entries), len(want))
+		}
+	})
+}
+
+// saveBoardFixture сохраняет boardFixture; рейтинги игроков после неё — boardRatings.
+func saveBoardFixture(t *testing.T, repo domain.GameRepository, now time.Time) map[string]uuid.UUID {
+	t.Helper()
+	users := saveUsers(t, repo, "a", "b", "c", "d")
+	logins := make(map[uuid.UUID]string, len(users))
+	for login, id := range users {
+		logins[id] = login
+	}
+	rate := func(x, o domain.Rating) (domain.Rating, domain.Rating) {
+		x.Rating, o.Rating = boardRatings[logins[x.PlayerID]], boardRatings[logins[o.PlayerID]]
+		return x, o
+	}
+
+	for i, fixture := range boardFixture {
+		finishedAt := now.Add(-fixture.finishedAgo)
+		game := &domain.Game{
+			GameId:    uuid.New(),
+			Mode:      fixture.mode,
+			Board:     domain.NewBoard(3, 3),
+			WinLength: 3,
+			Player_X:  users[fixture.x],
+			Player_O:  users[fixture.o],
+			State:     fixture.state,
+			WinnerPID: users[fixture.winner],
+			CreatedAt: finishedAt.Add(-time.Hour),
+			UpdatedAt: finishedAt,
+		}
+		if fixture.mode == domain.ULTIMATE {
+			game.Board = domain.NewBoard(domain.UltimateBoardSize, domain.UltimateBoardSize)
+			game.Ultimate = domain.NewUltimateBoard()
+		}
+		if fixture.state == domain.StatusTurn {
+			game.CurrentPID = game.Player_X
+		}
+
+		var err error
+		if game.Player_X != uuid.Nil && game.Player_O != uuid.Nil && fixture.state != domain.StatusTurn {
+			err = repo.SaveRatedGame(game, rate)
+		} else {
+			err = repo.SaveGame(game)
+		}
+		if err != nil {
+			t.Fatalf("save game %d: %v", i, err)
+		}
+	}
+	return users
+}
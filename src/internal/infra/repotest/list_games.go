@@ -0,0 +1,237 @@
+package repotest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"t03/internal/domain"
+)
+
+// Игроки партий из listFixture.
+const (
+	me    = "me"
+	other = "other"
+	third = "third"
+)
+
+type fixtureGame struct {
+	name       string
+	mode       domain.Gametype
+	state      domain.GameState
+	x, o       string // логины игроков, "" — нет игрока
+	createdAgo time.Duration
+}
+
+// Партии перечислены от новых к старым: в таком порядке их отдаёт ListGames.
+var listFixture = []fixtureGame{
+	{"my turn", domain.PVP, domain.StatusTurn, me, other, 1 * time.Minute},
+	{"open", domain.PVP, domain.StatusWaiting, other, "", 2 * time.Minute},
+	{"ai", domain.PVE, domain.StatusTurn, me, "", 3 * time.Minute},
+	{"my waiting", domain.PVP, domain.StatusWaiting, "", me, 4 * time.Minute},
+	{"won", domain.PVP, domain.StatusWin, other, me, 5 * time.Minute},
+	{"others playing", domain.PVP, domain.StatusTurn, other, third, 6 * time.Minute},
+	{"ai resigned", domain.PVE, domain.StatusResigned, me, "", 7 * time.Minute},
+	{"abandoned", domain.PVP, domain.StatusAbandoned, me, "", 8 * time.Minute},
+	{"others finished", domain.PVP, domain.StatusDraw, other, third, 9 * time.Minute},
+	{"old open", domain.PVP, domain.StatusWaiting, third, "", 10 * time.Minute},
+}
+
+// TestListGames проверяет фильтры и постраничную выдачу ListGames: все
+// реализации должны отдавать одни и те же партии в одном порядке.
+func TestListGames(t *testing.T, newRepo NewRepo) {
+	tests := []struct {
+		name   string
+		filter domain.GamesFilter
+		want   []string
+	}{
+		{"default", domain.GamesFilter{}, []string{"my turn", "open", "ai", "my waiting", "old open"}},
+		{"mine", domain.GamesFilter{Mine: true}, []string{"my turn", "my waiting"}},
+		{"open", domain.GamesFilter{Open: true}, []string{"open", "old open"}},
+		{"finished", domain.GamesFilter{Finished: true}, []string{"won", "ai resigned", "abandoned"}},
+		{"ai", domain.GamesFilter{AI: true}, []string{"ai"}},
+		{"mine and finished", domain.GamesFilter{Mine: true, Finished: true}, []string{"my turn", "my waiting", "won", "ai resigned", "abandoned"}},
+		{"all", domain.GamesFilter{Mine: true, Open: true, Finished: true, AI: true}, []string{"my turn", "open", "ai", "my waiting", "won", "ai resigned", "abandoned", "old open"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t)
+			users, ids := saveFixture(t, repo, time.Now().UTC().Truncate(time.Millisecond))
+
+			page, err := repo.ListGames(domain.GamesQuery{PlayerID: users[me], Filter: tt.filter, Limit: 20})
+			if err != nil {
+				t.Fatalf("list games: %v", err)
+			}
+			if got := names(page.Games, ids); !slices.Equal(got, tt.want) {
+				t.Errorf("games = %v, want %v", got, tt.want)
+			}
+			if page.Next != nil {
+				t.Errorf("next = %+v on the only page", page.Next)
+			}
+		})
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		repo := newRepo(t)
+		users, ids := saveFixture(t, repo, time.Now().UTC().Truncate(time.Millisecond))
+		page, err := repo.ListGames(domain.GamesQuery{PlayerID: users[me], Filter: domain.GamesFilter{Finished: true}, Limit: 1})
+		if err != nil {
+			t.Fatalf("list games: %v", err)
+		}
+		if len(page.Games) != 1 {
+			t.Fatalf("got %d games, want 1", len(page.Games))
+		}
+		game := page.Games[0]
+		if game.GameID != ids["won"] || game.Mode != domain.PVP || game.State != domain.StatusWin ||
+			game.Player_X != users[other] || game.Player_O != users[me] || game.LoginX != other || game.LoginO != me {
+			t.Errorf("summary = %+v", game)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		for _, limit := range []int{1, 2, 3, 5, 8} {
+			repo := newRepo(t)
+			users, ids := saveFixture(t, repo, time.Now().UTC().Truncate(time.Millisecond))
+			query := domain.GamesQuery{PlayerID: users[me], Filter: domain.GamesFilter{Mine: true, Open: true, Finished: true, AI: true}, Limit: limit}
+
+			var got []string
+			for pages := 0; ; pages++ {
+				if pages > len(listFixture) {
+					t.Fatalf("limit %d: pagination does not end", limit)
+				}
+				page, err := repo.ListGames(query)
+				if err != nil {
+					t.Fatalf("limit %d: list games: %v", limit, err)
+				}
+				if len(page.Games) > limit || len(page.Games) < limit && page.Next != nil {
+					t.Fatalf("limit %d: got %d games, next %+v", limit, len(page.Games), page.Next)
+				}
+				got = append(got, names(page.Games, ids)...)
+				if page.Next == nil {
+					break
+				}
+				last := page.Games[len(page.Games)-1]
+				if page.Next.GameID != last.GameID || !page.Next.CreatedAt.Equal(last.CreatedAt) {
+					t.Fatalf("limit %d: next %+v is not the last game %s", limit, page.Next, last.GameID)
+				}
+				query.After = page.Next
+			}
+			want := []string{"my turn", "open", "ai", "my waiting", "won", "ai resigned", "abandoned", "old open"}
+			if !slices.Equal(got, want) {
+				t.Errorf("limit %d: games = %v, want %v", limit, got, want)
+			}
+		}
+	})
+
+	// Партии, созданные в одну и ту же миллисекунду, упорядочены по id
+	// и не теряются на границе страниц.
+	t.Run("same creation time", func(t *testing.T) {
+		repo := newRepo(t)
+		users := saveUsers(t, repo, me, other, third)
+		createdAt := time.Now().UTC().Truncate(time.Millisecond)
+		var want []uuid.UUID
+		for range 5 {
+			game := fixtureGame{mode: domain.PVP, state: domain.StatusTurn, x: me, o: other}.build(users, createdAt)
+			if err := repo.SaveGame(game); err != nil {
+				t.Fatalf("save game: %v", err)
+			}
+			want = append(want, game.GameId)
+		}
+		slices.SortFunc(want, func(a, b uuid.UUID) int { return -compareIDs(a, b) })
+
+		query := domain.GamesQuery{PlayerID: users[me], Limit: 2}
+		var got []uuid.UUID
+		for {
+			page, err := repo.ListGames(query)
+			if err != nil {
+				t.Fatalf("list games: %v", err)
+			}
+			for _, game := range page.Games {
+				got = append(got, game.GameID)
+			}
+			if page.Next == nil || len(got) > len(want) {
+				break
+			}
+			query.After = page.Next
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("games = %v, want %v", got, want)
+		}
+	})
+}
+
+func saveUsers(t *testing.T, repo domain.GameRepository, logins ...string) map[string]uuid.UUID {
+	t.Helper()
+	users := make(map[string]uuid.UUID, len(logins))
+	for _, login := range logins {
+		user := &domain.User{ID: uuid.New(), Login: login, Password: "x"}
+		if err := repo.SaveUser(user); err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+		users[login] = user.ID
+	}
+	return users
+}
+
+// saveFixture сохраняет listFixture и возвращает id игроков и партий по имени.
+func saveFixture(t *testing.T, repo domain.GameRepository, now time.Time) (map[string]uuid.UUID, map[string]uuid.UUID) {
+	t.Helper()
+	users := saveUsers(t, repo, me, other, third)
+	ids := make(map[string]uuid.UUID, len(listFixture))
+	for _, fixture := range listFixture {
+		game := fixture.build(users, now.Add(-fixture.createdAgo))
+		if err := repo.SaveGame(game); err != nil {
+			t.Fatalf("save %s: %v", fixture.name, err)
+		}
+		ids[fixture.name] = game.GameId
+	}
+	return users, ids
+}
+
+func (f fixtureGame) build(users map[string]uuid.UUID, createdAt time.Time) *domain.Game {
+	game := &domain.Game{
+		GameId:    uuid.New(),
+		Mode:      f.mode,
+		Board:     domain.NewBoard(3, 3),
+		WinLength: 3,
+		Player_X:  users[f.x],
+		Player_O:  users[f.o],
+		State:     f.state,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	switch f.state {
+	case domain.StatusTurn:
+		game.CurrentPID = game.Player_X
+	case domain.StatusWin, domain.StatusResigned:
+		game.WinnerPID = game.Player_X
+	}
+	return game
+}
+
+func names(games []domain.GameSummary, ids map[string]uuid.UUID) []string {
+	byID := make(map[uuid.UUID]string, len(ids))
+	for name, id := range ids {
+		byID[id] = name
+	}
+	result := make([]string, 0, len(games))
+	for _, game := range games {
+		name, ok := byID[game.GameID]
+		if !ok {
+			name = game.GameID.String()
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+func compareIDs(a, b uuid.UUID) int {
+	switch as, bs := a.String(), b.String(); {
+	case as < bs:
+		return -1
+	case as > bs:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+// Package repotest — общие проверки реализаций domain.GameRepository.
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"t03/internal/domain"
+)
+
+// NewRepo создаёт пустое хранилище для одного подтеста.
+type NewRepo func(t *testing.T) domain.GameRepository
+
+// TestOptimisticLock проверяет, что сохранение устаревшей версии партии
+// возвращает domain.ErrConflict и ничего не меняет в хранилище.
+func TestOptimisticLock(t *testing.T, newRepo NewRepo) {
+	tests := []struct {
+		name string
+		// save сохраняет устаревшую копию партии, которая уже есть в хранилище.
+		save func(repo domain.GameRepository, stale *domain.Game) error
+	}{
+		{"save game", func(repo domain.GameRepository, stale *domain.Game) error {
+			return repo.SaveGame(stale)
+		}},
+		{"save rated game", func(repo domain.GameRepository, stale *domain.Game) error {
+			stale.State, stale.WinnerPID = domain.StatusWin, stale.Player_X
+			return repo.SaveRatedGame(stale, func(x, o domain.Rating) (domain.Rating, domain.Rating) {
+				return domain.RateGame(x, o, 1, stale.UpdatedAt, 0)
+			})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo(t)
+			game := newGame(t, repo)
+			if err := repo.SaveGame(game); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			stale := load(t, repo, game.GameId)
+			fresh := load(t, repo, game.GameId)
+			fresh.Board[0][0] = domain.X
+			if err := repo.SaveGame(fresh); err != nil {
+				t.Fatalf("save fresh copy: %v", err)
+			}
+
+			stale.Board[1][1] = domain.O
+			version := stale.Version
+			if err := tt.save(repo, stale); !errors.Is(err, domain.ErrConflict) {
+				t.Fatalf("save stale copy: got %v, want ErrConflict", err)
+			}
+			if stale.Version != version {
+				t.Errorf("stale version changed to %d, want %d", stale.Version, version)
+			}
+
+			stored := load(t, repo, game.GameId)
+			if stored.Version != fresh.Version || stored.Board[0][0] != domain.X || stored.Board[1][1] != domain.Empty || stored.State != domain.StatusTurn {
+				t.Errorf("stale save changed the game: version %d, state %d", stored.Version, stored.State)
+			}
+			for _, playerID := range []uuid.UUID{game.Player_X, game.Player_O} {
+				history, err := repo.GetRatingHistory(playerID, 10)
+				if err != nil {
+					t.Fatalf("rating history: %v", err)
+				}
+				if len(history) != 0 {
+					t.Errorf("rating changed on conflict: %+v", history)
+				}
+			}
+		})
+	}
+
+	t.Run("concurrent create", func(t *testing.T) {
+		repo := newRepo(t)
+		game := newGame(t, repo)
+		duplicate := game.Clone()
+		if err := repo.SaveGame(game); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := repo.SaveGame(duplicate); !errors.Is(err, domain.ErrConflict) {
+			t.Fatalf("create again: got %v, want ErrConflict", err)
+		}
+	})
+
+	t.Run("sequential saves", func(t *testing.T) {
+		repo := newRepo(t)
+		game := newGame(t, repo)
+		if err := repo.SaveGame(game); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		for i := range 3 {
+			if err := repo.SaveGame(game); err != nil {
+				t.Fatalf("save %d: %v", i+1, err)
+			}
+		}
+		if stored := load(t, repo, game.GameId); stored.Version != 4 || game.Version != 4 {
+			t.Errorf("version = %d (stored %d), want 4", game.Version, stored.Version)
+		}
+	})
+}
+
+// newGame возвращает несохранённую партию двух зарегистрированных игроков.
+func newGame(t *testing.T, repo domain.GameRepository) *domain.Game {
+	t.Helper()
+	players := [2]uuid.UUID{uuid.New(), uuid.New()}
+	for _, id := range players {
+		if err := repo.SaveUser(&domain.User{ID: id, Login: id.String(), Password: "x"}); err != nil {
+			t.Fatalf("save user: %v", err)
+		}
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return &domain.Game{
+		GameId:     uuid.New(),
+		Mode:       domain.PVP,
+		Board:      domain.NewBoard(3, 3),
+		WinLength:  3,
+		Player_X:   players[0],
+		Player_O:   players[1],
+		State:      domain.StatusTurn,
+		CurrentPID: players[0],
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func load(t *testing.T, repo domain.GameRepository, id uuid.UUID) *domain.Game {
+	t.Helper()
+	game, err := repo.GetGame(id.String())
+	if err != nil {
+		t.Fatalf("get game: %v", err)
+	}
+	return game
+}
@@ -0,0 +1,308 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"t03/internal/domain"
+	"t03/internal/infra/memory"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GameRepositoryImpl struct {
+	storage *Storage
+}
+
+func NewGameRepository(storage *Storage) domain.GameRepository {
+	return &GameRepositoryImpl{storage: storage}
+}
+
+const saveGameQuery = `
+	INSERT INTO game_sessions (id, board_state, width, height, win_length, difficulty, ai_engine, mode, player_x, player_o, state, turn, winner, version, created_at, updated_at, draw_offer, per_move_ms, clock_ms, increment_ms, clock_x_ms, clock_o_ms, turn_started_at, turn_deadline)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13, ?14 + 1, ?15, ?16, ?17, ?18, ?19, ?20, ?21, ?22, ?23, ?24)
+	ON CONFLICT (id) DO UPDATE
+	SET board_state = excluded.board_state,
+	    player_x    = excluded.player_x,
+	    player_o    = excluded.player_o,
+	    state       = excluded.state,
+	    turn        = excluded.turn,
+	    winner      = excluded.winner,
+	    version     = excluded.version,
+	    updated_at  = excluded.updated_at,
+	    draw_offer = excluded.draw_offer,
+	    clock_x_ms = excluded.clock_x_ms,
+	    clock_o_ms = excluded.clock_o_ms,
+	    turn_started_at = excluded.turn_started_at,
+	    turn_deadline = excluded.turn_deadline
+	WHERE game_sessions.version = ?14
+`
+
+const getGameQuery = `
+		SELECT id, board_state, width, height, win_length, difficulty, ai_engine, mode, player_x, player_o, state, turn, winner, version, created_at, updated_at, draw_offer,
+		       per_move_ms, clock_ms, increment_ms, clock_x_ms, clock_o_ms, turn_started_at, turn_deadline
+		FROM game_sessions
+		WHERE id = ?1
+	`
+const saveMoveQuery = `
+	INSERT INTO game_moves (game_id, move_number, player_id, row_idx, col_idx, symbol, made_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	ON CONFLICT (game_id, move_number) DO NOTHING
+`
+
+const getMovesQuery = `
+		SELECT game_id, move_number, player_id, row_idx, col_idx, symbol, made_at
+		FROM game_moves
+		WHERE game_id = ?1
+		ORDER BY move_number
+	`
+
+const staleGamesQuery = `
+    SELECT id
+    FROM game_sessions
+    WHERE state = ?1 AND updated_at < ?2
+    ORDER BY updated_at
+    LIMIT 100`
+
+const expiredGamesQuery = `
+    SELECT id
+    FROM game_sessions
+    WHERE state = 1 AND turn_deadline <= ?1
+    ORDER BY turn_deadline
+    LIMIT 100`
+
+// В отличие от Postgres-версии SUM обёрнут в COALESCE: у игрока без партий
+// агрегаты возвращают NULL.
+const statsQuery = `
+WITH my_games AS (
+    SELECT
+        id,
+        CASE
+            WHEN player_x = ?1 THEN 'X'
+            WHEN player_o = ?1 THEN 'O'
+        END AS role,
+        winner,
+        state,
+        -- партию бросили после начала: у ИИ-партии соперник есть всегда
+        mode = 1 OR winner <> '00000000-0000-0000-0000-000000000000' AS forfeited
+    FROM
+        game_sessions
+    WHERE
+        player_x = ?1
+        OR player_o = ?1
+)
+SELECT
+    COUNT(*) AS total_games,
+    COALESCE(SUM(
+        CASE
+            WHEN winner = ?1 THEN 1
+            ELSE 0
+        END
+    ), 0) AS wins,
+    COALESCE(SUM(
+        CASE
+            WHEN winner <> ?1
+            AND (state IN (3, 4, 5) OR state = 6 AND forfeited) THEN 1
+            ELSE 0
+        END
+    ), 0) AS losses,
+    COALESCE(SUM(
+        CASE
+            WHEN state = 2 THEN 1
+            ELSE 0
+        END
+    ), 0) AS draws,
+    COALESCE(SUM(
+        CASE
+            WHEN winner <> ?1
+            AND state = 6 AND forfeited THEN 1
+            ELSE 0
+        END
+    ), 0) AS abandoned,
+    COALESCE(ROUND(
+        100.0 * SUM(
+            CASE
+                WHEN winner = ?1 THEN 1
+                ELSE 0
+            END
+        ) / NULLIF(COUNT(*), 0),
+        1
+    ), 0) AS win_rate_pct
+FROM
+    my_games
+-- партия, брошенная до подключения соперника, не сыграна
+WHERE
+    state <> 6
+    OR forfeited;
+`
+
+func (repo *GameRepositoryImpl) GetPlayerStats(playerID uuid.UUID) (*domain.Stats, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+	var s domain.Stats
+	if err := repo.storage.db.QueryRowContext(ctx, statsQuery, playerID).Scan(
+		&s.TotalGames, &s.Wins, &s.Losses, &s.Draws, &s.Abandoned, &s.WinRatePct,
+	); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (repo *GameRepositoryImpl) SaveGame(game *domain.Game) error {
+	ctx, cancel := repo.storage.txContext()
+	defer cancel()
+
+	err := withTx(ctx, repo.storage.db, func(tx *sql.Tx) error {
+		return saveGame(ctx, tx, game)
+	})
+	if err != nil {
+		return err
+	}
+	game.Version++
+	return nil
+}
+
+func saveGame(ctx context.Context, tx *sql.Tx, game *domain.Game) error {
+	entity := memory.ToEntity(game)
+
+	result, err := tx.ExecContext(ctx, saveGameQuery, entity.GameId, entity.Board, entity.Width, entity.Height, entity.WinLength, entity.Difficulty, entity.AIEngine, entity.Mode, entity.Player_X, entity.Player_O, entity.State, entity.CurrentPID, entity.WinnerPID, entity.Version, entity.CreatedAt, entity.UpdatedAt, entity.DrawOffer,
+		entity.PerMoveMs, entity.ClockMs, entity.IncrementMs, entity.ClockXMs, entity.ClockOMs, entity.TurnStartedAt, entity.TurnDeadline)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return domain.ErrConflict
+	}
+	for _, move := range memory.ToMoveEntities(game) {
+		if _, err := tx.ExecContext(ctx, saveMoveQuery, move.GameId, move.Number, move.PlayerID, move.Row, move.Col, move.Symbol, move.MadeAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *GameRepositoryImpl) GetGame(id string) (*domain.Game, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	var entity memory.GameEntity
+
+	err := repo.storage.db.QueryRowContext(ctx, getGameQuery, id).Scan(&entity.GameId, &entity.Board, &entity.Width, &entity.Height, &entity.WinLength, &entity.Difficulty, &entity.AIEngine, &entity.Mode, &entity.Player_X, &entity.Player_O, &entity.State, &entity.CurrentPID, &entity.WinnerPID, &entity.Version, &entity.CreatedAt, &entity.UpdatedAt, &entity.DrawOffer,
+		&entity.PerMoveMs, &entity.ClockMs, &entity.IncrementMs, &entity.ClockXMs, &entity.ClockOMs, &entity.TurnStartedAt, &entity.TurnDeadline)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "game not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := memory.ToDomain(&entity)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.storage.db.QueryContext(ctx, getMovesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var moves []memory.MoveEntity
+	for rows.Next() {
+		var move memory.MoveEntity
+		if err := rows.Scan(&move.GameId, &move.Number, &move.PlayerID, &move.Row, &move.Col, &move.Symbol, &move.MadeAt); err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	game.Moves = memory.ToDomainMoves(moves)
+
+	return game, nil
+}
+
+func (repo *GameRepositoryImpl) ListGames(query domain.GamesQuery) (*domain.GamesPage, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	listQuery, args := memory.ListGamesQuery(query, "?")
+	rows, err := repo.storage.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var games []memory.GameSummaryEntity
+	for rows.Next() {
+		var g memory.GameSummaryEntity
+		if err := rows.Scan(&g.GameId, &g.Mode, &g.State, &g.Player_X, &g.Player_O, &g.LoginX, &g.LoginO, &g.CurrentPID, &g.WinnerPID, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return memory.ToDomainGamesPage(games, query.Limit), nil
+}
+
+func (repo *GameRepositoryImpl) GetExpiredGames(now time.Time) (uuid.UUIDs, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rows, err := repo.storage.db.QueryContext(ctx, expiredGamesQuery, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids uuid.UUIDs
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (repo *GameRepositoryImpl) GetStaleGames(state domain.GameState, updatedBefore time.Time) (uuid.UUIDs, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rows, err := repo.storage.db.QueryContext(ctx, staleGamesQuery, state, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids uuid.UUIDs
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
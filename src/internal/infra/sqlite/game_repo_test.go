@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"t03/internal/domain"
+	"t03/internal/infra/repotest"
+)
+
+func newTestRepo(t *testing.T) domain.GameRepository {
+	db, err := Open(Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return NewGameRepository(&Storage{db: db, timeout: 5 * time.Second, txTimeout: 5 * time.Second})
+}
+
+func TestOptimisticLock(t *testing.T) {
+	repotest.TestOptimisticLock(t, newTestRepo)
+}
+
+func TestListGames(t *testing.T) {
+	repotest.TestListGames(t, newTestRepo)
+}
+
+func TestLeaderboard(t *testing.T) {
+	repotest.TestLeaderboard(t, newTestRepo)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS game_moves;
+DROP TABLE IF EXISTS game_sessions;
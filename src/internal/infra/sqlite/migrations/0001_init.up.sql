@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id          TEXT PRIMARY KEY,
+    board_state TEXT    NOT NULL,
+    width       INTEGER NOT NULL DEFAULT 3,
+    height      INTEGER NOT NULL DEFAULT 3,
+    win_length  INTEGER NOT NULL DEFAULT 3,
+    difficulty  INTEGER NOT NULL DEFAULT 0,
+    ai_engine   TEXT    NOT NULL DEFAULT '',
+    mode        INTEGER NOT NULL,
+    player_x    TEXT    NOT NULL,
+    player_o    TEXT    NOT NULL,
+    state       INTEGER NOT NULL,
+    turn        TEXT    NOT NULL,
+    winner      TEXT    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS game_sessions_player_x_idx ON game_sessions (player_x);
+CREATE INDEX IF NOT EXISTS game_sessions_player_o_idx ON game_sessions (player_o);
+
+CREATE TABLE IF NOT EXISTS game_moves (
+    game_id     TEXT      NOT NULL REFERENCES game_sessions (id) ON DELETE CASCADE,
+    move_number INTEGER   NOT NULL,
+    player_id   TEXT      NOT NULL,
+    row_idx     INTEGER   NOT NULL,
+    col_idx     INTEGER   NOT NULL,
+    symbol      INTEGER   NOT NULL,
+    made_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (game_id, move_number)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id            TEXT PRIMARY KEY,
+    user_login    TEXT NOT NULL UNIQUE,
+    user_password TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT      NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx ON refresh_tokens (user_id);
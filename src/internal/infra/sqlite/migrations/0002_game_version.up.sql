@@ -0,0 +1 @@
+ALTER TABLE game_sessions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP INDEX game_sessions_created_at_idx;
+ALTER TABLE game_sessions DROP COLUMN updated_at;
+ALTER TABLE game_sessions DROP COLUMN created_at;
@@ -0,0 +1,11 @@
+-- ALTER TABLE в SQLite не принимает CURRENT_TIMESTAMP по умолчанию. Значение
+-- записано в формате, в котором драйвер хранит time.Time, чтобы сравнение
+-- строк совпадало со сравнением времени.
+ALTER TABLE game_sessions ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00 +0000 UTC';
+ALTER TABLE game_sessions ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00 +0000 UTC';
+
+UPDATE game_sessions
+SET created_at = COALESCE((SELECT MIN(made_at) FROM game_moves WHERE game_id = game_sessions.id), created_at),
+    updated_at = COALESCE((SELECT MAX(made_at) FROM game_moves WHERE game_id = game_sessions.id), updated_at);
+
+CREATE INDEX game_sessions_created_at_idx ON game_sessions (created_at DESC, id DESC);
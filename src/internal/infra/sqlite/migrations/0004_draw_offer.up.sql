@@ -0,0 +1 @@
+ALTER TABLE game_sessions ADD COLUMN draw_offer TEXT NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
@@ -0,0 +1,9 @@
+UPDATE game_sessions SET state = 3 WHERE state = 5;
+DROP INDEX game_sessions_turn_deadline_idx;
+ALTER TABLE game_sessions DROP COLUMN turn_deadline;
+ALTER TABLE game_sessions DROP COLUMN turn_started_at;
+ALTER TABLE game_sessions DROP COLUMN clock_o_ms;
+ALTER TABLE game_sessions DROP COLUMN clock_x_ms;
+ALTER TABLE game_sessions DROP COLUMN increment_ms;
+ALTER TABLE game_sessions DROP COLUMN clock_ms;
+ALTER TABLE game_sessions DROP COLUMN per_move_ms;
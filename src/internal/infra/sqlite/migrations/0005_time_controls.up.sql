@@ -0,0 +1,9 @@
+ALTER TABLE game_sessions ADD COLUMN per_move_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_sessions ADD COLUMN clock_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_sessions ADD COLUMN increment_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_sessions ADD COLUMN clock_x_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_sessions ADD COLUMN clock_o_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE game_sessions ADD COLUMN turn_started_at TIMESTAMP;
+ALTER TABLE game_sessions ADD COLUMN turn_deadline TIMESTAMP;
+
+CREATE INDEX game_sessions_turn_deadline_idx ON game_sessions (turn_deadline) WHERE state = 1;
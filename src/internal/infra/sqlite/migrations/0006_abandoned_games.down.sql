@@ -0,0 +1,3 @@
+UPDATE game_sessions SET state = 3 WHERE state = 6 AND winner <> '00000000-0000-0000-0000-000000000000';
+UPDATE game_sessions SET state = 0 WHERE state = 6;
+DROP INDEX game_sessions_state_updated_at_idx;
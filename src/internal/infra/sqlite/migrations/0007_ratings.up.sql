@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS player_ratings (
+    player_id  TEXT    PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    rating     REAL    NOT NULL,
+    rd         REAL    NOT NULL,
+    volatility REAL    NOT NULL,
+    games      INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS rating_history (
+    game_id    TEXT      NOT NULL REFERENCES game_sessions (id) ON DELETE CASCADE,
+    player_id  TEXT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating     REAL      NOT NULL,
+    rd         REAL      NOT NULL,
+    volatility REAL      NOT NULL,
+    delta      REAL      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (game_id, player_id)
+);
+
+CREATE INDEX IF NOT EXISTS rating_history_player_id_idx ON rating_history (player_id, created_at DESC);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS game_sessions_finished_idx ON game_sessions (updated_at) WHERE state >= 2;
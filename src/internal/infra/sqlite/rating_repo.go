@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"t03/internal/domain"
+	"t03/internal/infra/memory"
+
+	"github.com/google/uuid"
+)
+
+const ratingQuery = `
+    SELECT player_id, rating, rd, volatility, games, updated_at
+    FROM player_ratings
+    WHERE player_id = ?1`
+
+const saveRatingQuery = `
+    INSERT INTO player_ratings (player_id, rating, rd, volatility, games, updated_at)
+    VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+    ON CONFLICT (player_id) DO UPDATE
+    SET rating = excluded.rating,
+        rd = excluded.rd,
+        volatility = excluded.volatility,
+        games = excluded.games,
+        updated_at = excluded.updated_at`
+
+const saveRatingChangeQuery = `
+    INSERT INTO rating_history (game_id, player_id, rating, rd, volatility, delta, created_at)
+    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)`
+
+const ratingHistoryQuery = `
+    SELECT game_id, player_id, rating, rd, volatility, delta, created_at
+    FROM rating_history
+    WHERE player_id = ?1
+    ORDER BY created_at DESC
+    LIMIT ?2`
+
+// Соединение с базой одно, поэтому транзакция и так видит рейтинги без гонок.
+func (repo *GameRepositoryImpl) SaveRatedGame(game *domain.Game, rate domain.RateFunc) error {
+	ctx, cancel := repo.storage.txContext()
+	defer cancel()
+
+	err := withTx(ctx, repo.storage.db, func(tx *sql.Tx) error {
+		if err := saveGame(ctx, tx, game); err != nil {
+			return err
+		}
+		x, err := getRating(ctx, tx, game.Player_X)
+		if err != nil {
+			return err
+		}
+		o, err := getRating(ctx, tx, game.Player_O)
+		if err != nil {
+			return err
+		}
+
+		newX, newO := rate(x, o)
+		for _, r := range [][2]domain.Rating{{x, newX}, {o, newO}} {
+			entity := memory.RatingToEntity(r[1])
+			change := memory.RatingChangeToEntity(domain.NewRatingChange(game.GameId, r[0], r[1]))
+			if _, err := tx.ExecContext(ctx, saveRatingQuery, entity.PlayerID, entity.Rating, entity.RD, entity.Volatility, entity.Games, entity.UpdatedAt); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, saveRatingChangeQuery, change.GameID, change.PlayerID, change.Rating, change.RD, change.Volatility, change.Delta, change.CreatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	game.Version++
+	return nil
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func getRating(ctx context.Context, db queryer, playerID uuid.UUID) (domain.Rating, error) {
+	var entity memory.RatingEntity
+	err := db.QueryRowContext(ctx, ratingQuery, playerID).Scan(&entity.PlayerID, &entity.Rating, &entity.RD, &entity.Volatility, &entity.Games, &entity.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewRating(playerID), nil
+	}
+	if err != nil {
+		return domain.Rating{}, err
+	}
+	return memory.RatingToDomain(entity), nil
+}
+
+func (repo *GameRepositoryImpl) GetRating(playerID uuid.UUID) (*domain.Rating, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rating, err := getRating(ctx, repo.storage.db, playerID)
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (repo *GameRepositoryImpl) GetRatingHistory(playerID uuid.UUID, limit int) ([]domain.RatingChange, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	rows, err := repo.storage.db.QueryContext(ctx, ratingHistoryQuery, playerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []memory.RatingChangeEntity
+	for rows.Next() {
+		var e memory.RatingChangeEntity
+		if err := rows.Scan(&e.GameID, &e.PlayerID, &e.Rating, &e.RD, &e.Volatility, &e.Delta, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entities = append(entities, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return memory.RatingChangesToDomain(entities), nil
+}
+
+func (repo *GameRepositoryImpl) GetLeaderboard(query domain.LeaderboardQuery) ([]domain.LeaderboardEntry, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	boardQuery, args := memory.LeaderboardQuery(query, "?")
+	rows, err := repo.storage.db.QueryContext(ctx, boardQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []memory.LeaderboardEntryEntity
+	for rows.Next() {
+		var e memory.LeaderboardEntryEntity
+		if err := rows.Scan(&e.PlayerID, &e.Login, &e.Games, &e.Wins, &e.Losses, &e.Draws, &e.WinRatePct, &e.Rating, &e.RD, &e.Volatility, &e.RatedGames, &e.RatedAt); err != nil {
+			return nil, err
+		}
+		entities = append(entities, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return memory.LeaderboardToDomain(entities), nil
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"time"
+
+	"go.uber.org/fx"
+	"t03/internal/infra/migrate"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type Storage struct {
+	db        *sql.DB
+	timeout   time.Duration
+	txTimeout time.Duration
+}
+
+type Config struct {
+	Path         string
+	QueryTimeout time.Duration
+	TxTimeout    time.Duration // на транзакцию из нескольких запросов
+	AutoMigrate  bool
+}
+
+func NewStorage(lc fx.Lifecycle, cfg Config) (*Storage, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if !cfg.AutoMigrate {
+				return nil
+			}
+			migrator, err := NewMigrator(db)
+			if err != nil {
+				return err
+			}
+			return migrator.Up(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			return db.Close()
+		},
+	})
+
+	return &Storage{db: db, timeout: cfg.QueryTimeout, txTimeout: cfg.TxTimeout}, nil
+}
+
+func Open(cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite допускает только одного писателя, остальные соединения получали бы SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+func NewMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	source, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db, source)
+}
+
+func (s *Storage) queryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+// txContext ограничивает транзакцию целиком: она может ждать, пока другой
+// писатель освободит базу, и таймаута одного запроса под нагрузкой ей не хватает.
+func (s *Storage) txContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.txTimeout)
+}
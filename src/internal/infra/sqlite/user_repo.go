@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"t03/internal/domain"
+	"t03/internal/infra/memory"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (repo *GameRepositoryImpl) SaveUser(user *domain.User) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	entity := memory.UserToEntity(user)
+
+	_, err := repo.storage.db.ExecContext(ctx, `
+		INSERT INTO users (id, user_login, user_password)
+		VALUES (?1, ?2, ?3)
+	`, entity.ID, entity.Login, entity.Password)
+
+	return err
+}
+
+func (repo *GameRepositoryImpl) GetUser(login string) (*domain.User, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+	var entity memory.UserEntity
+	err := repo.storage.db.QueryRowContext(ctx, `
+		SELECT id, user_login, user_password
+		FROM users
+		WHERE user_login = ?1
+	`, login).Scan(&entity.ID, &entity.Login, &entity.Password)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.UserToDomain(&entity), nil
+}
+
+func (repo *GameRepositoryImpl) UpdateUserPassword(userID uuid.UUID, password string) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	_, err := repo.storage.db.ExecContext(ctx, `
+		UPDATE users
+		SET user_password = ?2
+		WHERE id = ?1
+	`, userID, password)
+
+	return err
+}
+
+func (repo *GameRepositoryImpl) SaveRefreshToken(token *domain.RefreshToken) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	entity := memory.RefreshTokenToEntity(token)
+
+	_, err := repo.storage.db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5)
+	`, entity.ID, entity.UserID, entity.TokenHash, entity.ExpiresAt, entity.CreatedAt)
+
+	return err
+}
+
+func (repo *GameRepositoryImpl) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+	var entity memory.RefreshTokenEntity
+	err := repo.storage.db.QueryRowContext(ctx, `
+		SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = ?1
+	`, tokenHash).Scan(&entity.ID, &entity.UserID, &entity.TokenHash, &entity.ExpiresAt, &entity.CreatedAt, &entity.RevokedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.NewError(domain.ErrNotFound, "refresh token not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.RefreshTokenToDomain(&entity), nil
+}
+
+func (repo *GameRepositoryImpl) RevokeRefreshToken(tokenHash string) (bool, error) {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	result, err := repo.storage.db.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = ?2
+		WHERE token_hash = ?1 AND revoked_at IS NULL
+	`, tokenHash, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (repo *GameRepositoryImpl) RevokeUserRefreshTokens(userID uuid.UUID) error {
+	ctx, cancel := repo.storage.queryContext()
+	defer cancel()
+
+	_, err := repo.storage.db.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = ?2
+		WHERE user_id = ?1 AND revoked_at IS NULL
+	`, userID, time.Now().UTC())
+
+	return err
+}